@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"backend/internal/config"
+	"backend/internal/logger"
+	"backend/internal/metclient"
+	"backend/internal/repository"
+	"backend/internal/service"
+)
+
+// runExport はミュージアムを静的なHTMLギャラリーのZIPに書き出すサブコマンド
+// GET /api/v1/museums/{id}/export.zip と同じ内容を、サーバーを起動せずにファイルへ保存する
+//
+//	go run ./cmd/server export -museum 1 [-user 2] [-o museum-1.zip]
+//
+// -userを省略した場合はDBを操作できる管理者として扱い、公開設定を問わず書き出す
+func runExport(args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	museumID := fs.Int("museum", 0, "書き出すミュージアムのID（必須）")
+	userID := fs.Int("user", 0, "このユーザーとして閲覧権限を確認する（省略時は確認しない）")
+	out := fs.String("o", "", "出力するZIPのパス（省略時は museum-{id}.zip）")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *museumID <= 0 {
+		fmt.Fprintln(os.Stderr, "export: -museum is required")
+		fs.Usage()
+		return 2
+	}
+
+	cfg := config.Load()
+	log := logger.New(cfg.Env)
+	if err := exportMuseum(context.Background(), cfg, log, *museumID, *userID, *out); err != nil {
+		log.Error("export failed", slog.Int("museumId", *museumID), slog.String("error", err.Error()))
+		return 1
+	}
+	return 0
+}
+
+func exportMuseum(ctx context.Context, cfg config.Config, log *slog.Logger, museumID, userID int, out string) error {
+	if !cfg.DBEnabled {
+		return fmt.Errorf("export requires the database (DB_ENABLED=true)")
+	}
+	db, err := sql.Open("pgx", cfg.PostgresDSN())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("connect postgres: %w", err)
+	}
+
+	museumRepo := repository.NewPostgresMuseumRepository(db)
+	access := service.NewMuseumAccess(museumRepo, repository.NewPostgresMuseumMemberRepository(db))
+	metCfg := metConfig(cfg)
+	met := service.NewMetService(metclient.New(metCfg))
+	images := service.NewImageService(met, metclient.New(imageClientConfig(metCfg)), newImageCache(cfg, db, log), log)
+	exportSvc := service.NewExportService(museumRepo, repository.NewPostgresMuseumArtworkRepository(db), access, met, images, log)
+
+	var export *service.MuseumExport
+	if userID > 0 {
+		export, err = exportSvc.Prepare(ctx, museumID, userID)
+	} else {
+		export, err = exportSvc.PrepareShared(ctx, museumID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		out = export.FileName()
+	}
+	// 途中で失敗しても壊れたZIPを残さないよう、一時ファイルに書いてからリネームする
+	tmp, err := os.CreateTemp(filepath.Dir(out), filepath.Base(out)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := export.WriteZip(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), out); err != nil {
+		return err
+	}
+
+	log.Info("museum exported", slog.Int("museumId", museumID), slog.Int("artworks", len(export.Artworks)), slog.String("path", out))
+	return nil
+}
@@ -3,6 +3,7 @@ package main
 import (
     "context"
     "database/sql"
+    "errors"
     "fmt"
     "log/slog"
     "net/http"
@@ -12,27 +13,64 @@ import (
     "time"
 
     "backend/internal/config"
+    "backend/internal/health"
     "backend/internal/httpserver"
     "backend/internal/logger"
+    "backend/internal/metclient"
+    "backend/internal/metrics"
     "backend/internal/repository"
     "backend/internal/service"
+    "backend/internal/storage"
+    "backend/internal/tracing"
     _ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// trashPurgeInterval はゴミ箱の物理削除ジョブの実行間隔
+const trashPurgeInterval = time.Hour
+
+// paletteBackfillInterval は展示中の作品の代表色を埋めるジョブの実行間隔
+const paletteBackfillInterval = time.Minute
+
+// errDBFallback はPostgreSQLに接続できずメモリのリポジトリで動いている場合の/readyzのエラー
+var errDBFallback = errors.New("postgres not connected; running with in-memory fallback")
+
 // main wires dependencies manually. A wire-ready provider set is also included
 // under internal/di for future codegen-based wiring.
 func main() {
+    // サブコマンド（サーバーを起動せずに実行する管理用の処理）
+    if len(os.Args) > 1 && os.Args[1] == "export" {
+        os.Exit(runExport(os.Args[2:]))
+    }
+
     cfg := config.Load()
     log := logger.New(cfg.Env)
 
+    // トレーシング（OTEL_TRACES_EXPORTER=noneのときはスパンを記録しない）
+    shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter, cfg.ServiceName)
+    if err != nil {
+        log.Error("tracing setup failed; continuing without tracing", slog.String("error", err.Error()))
+        shutdownTracing = func(context.Context) error { return nil }
+    }
+
     // Repository and service wiring
     var repo repository.ItemRepository
     var museumRepo repository.MuseumRepository
+    var engagementRepo repository.MuseumEngagementRepository
+    var commentRepo repository.CommentRepository
+    var artworkRepo repository.MuseumArtworkRepository
+    var followRepo repository.FollowRepository
+    var activityRepo repository.ActivityRepository
+    var shareRepo repository.ShareTokenRepository
+    var memberRepo repository.MuseumMemberRepository
+    var revisionRepo repository.MuseumRevisionRepository
+    var paletteRepo repository.ArtworkPaletteRepository
+    var layoutRepo repository.MuseumLayoutRepository
     var pgDB *sql.DB
 
     if cfg.DBEnabled {
         dsn := cfg.PostgresDSN()
-        if db, err := sql.Open("pgx", dsn); err != nil {
+        // クエリごとにスパンを作る（リポジトリはリクエストのコンテキストを渡す）
+        if db, err := tracing.OpenDB("pgx", dsn); err != nil {
             log.Error("postgres connect failed; falling back to memory", slog.String("error", err.Error()))
             mem := repository.NewInMemoryItemRepository()
             _ = mem.MustSeed("First item", "Second item")
@@ -53,24 +91,109 @@ func main() {
 
             // Museum リポジトリの初期化
             museumRepo = repository.NewPostgresMuseumRepository(pgDB)
+            engagementRepo = repository.NewPostgresMuseumEngagementRepository(pgDB)
+            commentRepo = repository.NewPostgresCommentRepository(pgDB)
+            artworkRepo = repository.NewPostgresMuseumArtworkRepository(pgDB)
+            followRepo = repository.NewPostgresFollowRepository(pgDB)
+            activityRepo = repository.NewPostgresActivityRepository(pgDB)
+            shareRepo = repository.NewPostgresShareTokenRepository(pgDB)
+            memberRepo = repository.NewPostgresMuseumMemberRepository(pgDB)
+            revisionRepo = repository.NewPostgresMuseumRevisionRepository(pgDB)
+            paletteRepo = repository.NewPostgresArtworkPaletteRepository(pgDB)
+            layoutRepo = repository.NewPostgresMuseumLayoutRepository(pgDB)
         }
     } else {
         mem := repository.NewInMemoryItemRepository()
         _ = mem.MustSeed("First item", "Second item")
         repo = mem
     }
-    svc := service.NewItemService(repo)
+    // コネクションプールの状態（sql.DB.Stats）を/metricsで公開する
+    if pgDB != nil {
+        if err := metrics.RegisterDB(pgDB, cfg.DBName); err != nil {
+            log.Error("failed to register db metrics", slog.String("error", err.Error()))
+        }
+    }
 
-    var museumSvc *service.MuseumService
+    // MET APIへのリクエストは共有クライアントでレート制限・再試行・サーキットブレーカーをまとめて扱う
+    metCfg := metConfig(cfg)
+    metClient := metclient.New(metCfg)
+
+    svcs := httpserver.Services{
+        Item: service.NewItemService(repo),
+        // MET APIを使うサービスはDB不要
+        Met: service.NewMetService(metClient),
+    }
+
+    // 作品画像の縮小・変換（画像はMET APIと別のホストなので、ブレーカーを分けた別のクライアントで取得する）
+    imageCache := newImageCache(cfg, pgDB, log)
+    svcs.Image = service.NewImageService(svcs.Met, metclient.New(imageClientConfig(metCfg)), imageCache, log)
+
+    // DBが必要なサービス（未接続時はnilのままでルートも登録されない）
+    var palettes service.PaletteLookup // 代表色はDBに保存するため、未接続時は一覧に付け加えない
     if museumRepo != nil {
-        museumSvc = service.NewMuseumService(museumRepo)
+        svcs.Palette = service.NewPaletteService(paletteRepo, svcs.Image, log)
+        palettes = svcs.Palette
+        activitySvc := service.NewActivityService(activityRepo, log)
+        moderator := service.NewWordListModerator(cfg.CommentBannedWords)
+        access := service.NewMuseumAccess(museumRepo, memberRepo)
+        revisionSvc := service.NewRevisionService(revisionRepo, access, log)
+
+        svcs.Museum = service.NewMuseumService(museumRepo, engagementRepo, access, activitySvc, revisionSvc)
+        svcs.Comment = service.NewCommentService(commentRepo, access, moderator)
+        svcs.Artwork = service.NewArtworkService(artworkRepo, museumRepo, access, activitySvc, revisionSvc, palettes)
+        svcs.Follow = service.NewFollowService(followRepo)
+        svcs.Activity = activitySvc
+        svcs.Share = service.NewShareService(shareRepo, access)
+        svcs.Embed = service.NewEmbedService(svcs.Museum, svcs.Share, cfg.PublicBaseURL, cfg.FrontendURL)
+        svcs.Member = service.NewMemberService(memberRepo, access)
+        svcs.Revision = revisionSvc
+        if store, files, err := newBlobStore(cfg); err != nil {
+            log.Error("blob store unavailable; cover uploads disabled", slog.String("error", err.Error()))
+        } else {
+            svcs.Cover = service.NewCoverService(museumRepo, access, store, revisionSvc, cfg.CoverMaxBytes)
+            svcs.BlobFiles = files
+        }
+        svcs.Layout = service.NewLayoutService(layoutRepo, artworkRepo, museumRepo, access, svcs.Image, imageCache, revisionSvc, log)
+        svcs.Export = service.NewExportService(museumRepo, artworkRepo, access, svcs.Met, svcs.Image, log)
+        svcs.Trash = service.NewTrashService(museumRepo, artworkRepo, access, revisionSvc,
+            time.Duration(cfg.TrashRetentionDays)*24*time.Hour, log)
     }
+    svcs.ArtworkSearch = service.NewArtworkSearchService(metClient, palettes)
 
-    // ArtworkSearchServiceを作成
-    artworkSearchSvc := service.NewArtworkSearchService()
+    // /readyzの確認（DB無効時はメモリで動かす想定なのでDBの確認を行わない）
+    var checks []health.Check
+    if cfg.DBEnabled {
+        checks = append(checks,
+            health.Check{Name: "database", Critical: true, Run: func(ctx context.Context) error {
+                if pgDB == nil {
+                    return errDBFallback
+                }
+                return pgDB.PingContext(ctx)
+            }},
+            health.Check{Name: "migrations", Critical: true, Run: func(ctx context.Context) error {
+                if pgDB == nil {
+                    return errDBFallback
+                }
+                return repository.CheckSchema(ctx, pgDB)
+            }},
+        )
+    }
+    if cfg.ReadyzCheckMET {
+        checks = append(checks, health.Check{Name: "met", Run: svcs.Met.Ping})
+    }
+    svcs.Readiness = health.NewReadiness(checks...)
 
-    // Routerは (cfg, log, itemSvc, museumSvc, artworkSearchSvc) のシグネチャ
-    router := httpserver.NewRouter(cfg, log, svc, museumSvc, artworkSearchSvc)
+    router := httpserver.NewRouter(cfg, log, svcs)
+
+    // バックグラウンドジョブ（シャットダウン時に停止する）
+    jobCtx, stopJobs := context.WithCancel(context.Background())
+    defer stopJobs()
+    if svcs.Trash != nil {
+        go svcs.Trash.RunPurge(jobCtx, trashPurgeInterval)
+    }
+    if svcs.Palette != nil {
+        go svcs.Palette.RunBackfill(jobCtx, paletteBackfillInterval)
+    }
 
 
     srv := &http.Server{
@@ -95,6 +218,14 @@ func main() {
     signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
     <-quit
     log.Info("shutdown signal received")
+    stopJobs()
+
+    // /readyzを503にして、ロードバランサーが新しいリクエストを送らなくなるまで待ってから停止する
+    svcs.Readiness.SetDraining()
+    if cfg.ShutdownDrainDelay > 0 {
+        log.Info("draining before shutdown", slog.Int("seconds", cfg.ShutdownDrainDelay))
+        time.Sleep(time.Duration(cfg.ShutdownDrainDelay) * time.Second)
+    }
 
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
@@ -104,5 +235,69 @@ func main() {
     if pgDB != nil {
         _ = pgDB.Close()
     }
+    // 未送信のスパンを書き出す
+    if err := shutdownTracing(ctx); err != nil {
+        log.Error("tracing shutdown failed", slog.String("error", err.Error()))
+    }
     log.Info("server stopped")
 }
+
+// metConfig は環境変数からMET APIクライアントの設定を作る
+func metConfig(cfg config.Config) metclient.Config {
+    metCfg := metclient.DefaultConfig()
+    metCfg.MaxAttempts = cfg.METMaxAttempts
+    metCfg.AttemptTimeout = time.Duration(cfg.METAttemptTimeoutSeconds) * time.Second
+    metCfg.RateLimit = cfg.METRateLimit
+    metCfg.BreakerThreshold = cfg.METBreakerThreshold
+    metCfg.BreakerCooldown = time.Duration(cfg.METBreakerCooldownSeconds) * time.Second
+    return metCfg
+}
+
+// imageClientConfig は作品画像のダウンロード用クライアントの設定（画像は大きいため試行のタイムアウトを長くする）
+func imageClientConfig(metCfg metclient.Config) metclient.Config {
+    metCfg.AttemptTimeout = 30 * time.Second
+    return metCfg
+}
+
+// newImageCache はIMAGE_CACHEに応じた画像キャッシュを作成する
+// 使えない場合はキャッシュなし（毎回METから取得して変換する）で動かす
+func newImageCache(cfg config.Config, pgDB *sql.DB, log *slog.Logger) repository.ImageCacheRepository {
+    switch cfg.ImageCache {
+    case "postgres":
+        if pgDB != nil {
+            return repository.NewPostgresImageCache(pgDB)
+        }
+        log.Warn("image cache disabled: postgres is not available")
+    case "disk":
+        cache, err := repository.NewFileImageCache(cfg.ImageCacheDir)
+        if err == nil {
+            return cache
+        }
+        log.Warn("image cache disabled", slog.String("error", err.Error()))
+    }
+    return nil
+}
+
+// newBlobStore はBLOB_STOREに応じたアップロードファイルの保存先を作成する
+// ローカルの場合は保存したファイルを返すハンドラーも返す
+func newBlobStore(cfg config.Config) (storage.BlobStore, http.Handler, error) {
+    switch cfg.BlobStore {
+    case "s3":
+        store, err := storage.NewS3BlobStore(storage.S3Config{
+            Endpoint:        cfg.S3Endpoint,
+            Region:          cfg.S3Region,
+            Bucket:          cfg.S3Bucket,
+            AccessKeyID:     cfg.S3AccessKeyID,
+            SecretAccessKey: cfg.S3SecretAccessKey,
+            PublicURL:       cfg.S3PublicURL,
+        })
+        return store, nil, err
+    case "local":
+        store, err := storage.NewLocalBlobStore(cfg.BlobLocalDir, cfg.BlobPublicURL)
+        if err != nil {
+            return nil, nil, err
+        }
+        return store, store.Handler(), nil
+    }
+    return nil, nil, fmt.Errorf("unknown BLOB_STORE %q", cfg.BlobStore)
+}
@@ -1,8 +1,11 @@
 package config
 
 import (
+    "fmt"
+    "net/netip"
     "net/url"
     "os"
+    "path/filepath"
     "strconv"
     "strings"
 )
@@ -22,6 +25,53 @@ type Config struct {
     DBName    string
     DBSSLMode string
     DBMigrate bool // create tables if not exists
+
+    // Comment moderation
+    CommentBannedWords []string
+
+    // Trash (soft-deleted museums and artworks)
+    TrashRetentionDays int
+
+    // Upper limit of request bodies in bytes (413 when exceeded)
+    MaxRequestBodyBytes int64
+
+    // Reverse proxies whose X-Forwarded-For is trusted (empty = use RemoteAddr only)
+    TrustedProxies []netip.Prefix
+
+    // Outbound MET API client
+    METMaxAttempts            int     // attempts per request including the first one
+    METAttemptTimeoutSeconds  int     // timeout of a single attempt
+    METRateLimit              float64 // requests per second (the MET asks for at most 80)
+    METBreakerThreshold       int     // consecutive failures that open the circuit breaker
+    METBreakerCooldownSeconds int     // seconds before a probe request is let through
+
+    // Resized artwork images (/api/v1/images)
+    ImageCache    string // disk, postgres, none
+    ImageCacheDir string // directory of the disk cache
+
+    // Uploaded files (museum covers)
+    BlobStore         string // local, s3
+    BlobLocalDir      string // directory of the local store
+    BlobPublicURL     string // URL the local store is served under (must end with /blobs)
+    S3Endpoint        string
+    S3Region          string
+    S3Bucket          string
+    S3AccessKeyID     string
+    S3SecretAccessKey string
+    S3PublicURL       string // defaults to S3Endpoint/S3Bucket
+    CoverMaxBytes     int64  // upper limit of uploaded cover images (413 when exceeded)
+
+    // Share pages (/m/{id}) and oEmbed need absolute URLs
+    PublicBaseURL string // URL this server is reachable at from the internet
+    FrontendURL   string // URL of the frontend app that share pages link to
+
+    // Readiness (/readyz) and graceful shutdown
+    ReadyzCheckMET     bool // also report MET API reachability (never fails readiness)
+    ShutdownDrainDelay int  // seconds /readyz reports draining before the server stops accepting requests
+
+    // OpenTelemetry tracing
+    TracesExporter string // none, stdout, otlp
+    ServiceName    string
 }
 
 func getEnv(key, def string) string {
@@ -31,6 +81,42 @@ func getEnv(key, def string) string {
     return def
 }
 
+// getEnvInt reads an integer variable, falling back to def when it is unset,
+// malformed or below min.
+func getEnvInt(key string, def, min int) int {
+    v, err := strconv.Atoi(getEnv(key, strconv.Itoa(def)))
+    if err != nil || v < min {
+        return def
+    }
+    return v
+}
+
+// splitList splits a comma-separated value, trimming blanks.
+func splitList(v string) []string {
+    out := []string{}
+    for _, s := range strings.Split(v, ",") {
+        s = strings.TrimSpace(s)
+        if s != "" {
+            out = append(out, s)
+        }
+    }
+    return out
+}
+
+// parsePrefixes parses CIDRs and plain IP addresses (as single-address
+// prefixes), skipping malformed entries.
+func parsePrefixes(values []string) []netip.Prefix {
+    out := []netip.Prefix{}
+    for _, v := range values {
+        if p, err := netip.ParsePrefix(v); err == nil {
+            out = append(out, p.Masked())
+        } else if a, err := netip.ParseAddr(v); err == nil {
+            out = append(out, netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()))
+        }
+    }
+    return out
+}
+
 // Load reads configuration from environment variables with sensible defaults.
 func Load() Config {
     // Prefer PORT (12-factor), fallback to BACKEND_PORT
@@ -41,14 +127,7 @@ func Load() Config {
     }
 
     // Allow comma-separated origins; default to common local dev URL
-    cors := getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173")
-    origins := []string{}
-    for _, o := range strings.Split(cors, ",") {
-        o = strings.TrimSpace(o)
-        if o != "" {
-            origins = append(origins, o)
-        }
-    }
+    origins := splitList(getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"))
 
     env := getEnv("APP_ENV", getEnv("ENV", "development"))
 
@@ -68,6 +147,70 @@ func Load() Config {
     dbSSLMode := getEnv("DB_SSLMODE", "prefer")
     dbMigrate := strings.ToLower(getEnv("DB_MIGRATE", "true")) == "true"
 
+    // Comma-separated words rejected by the local comment moderation filter
+    commentBannedWords := splitList(getEnv("COMMENT_BANNED_WORDS", ""))
+
+    // Days to keep soft-deleted museums/artworks before the purge job removes them
+    trashRetentionDays, err := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+    if err != nil || trashRetentionDays <= 0 {
+        trashRetentionDays = 30
+    }
+
+    // Request bodies larger than this are rejected with 413 (default 1 MiB)
+    maxRequestBodyBytes, err := strconv.ParseInt(getEnv("MAX_REQUEST_BODY_BYTES", "1048576"), 10, 64)
+    if err != nil || maxRequestBodyBytes <= 0 {
+        maxRequestBodyBytes = 1 << 20
+    }
+
+    // Comma-separated CIDRs/IPs of reverse proxies in front of this server
+    // (e.g. the load balancer of the hosting platform). X-Forwarded-For is
+    // ignored unless the request comes from one of them.
+    trustedProxies := parsePrefixes(splitList(getEnv("TRUSTED_PROXIES", "")))
+
+    // MET API client resilience (retries, timeouts, rate limit, circuit breaker)
+    metMaxAttempts := getEnvInt("MET_MAX_ATTEMPTS", 3, 1)
+    metAttemptTimeout := getEnvInt("MET_ATTEMPT_TIMEOUT_SECONDS", 10, 1)
+    metRateLimit, err := strconv.ParseFloat(getEnv("MET_RATE_LIMIT_RPS", "50"), 64)
+    if err != nil || metRateLimit < 0 {
+        metRateLimit = 50
+    }
+    metBreakerThreshold := getEnvInt("MET_BREAKER_THRESHOLD", 5, 0)
+    metBreakerCooldown := getEnvInt("MET_BREAKER_COOLDOWN_SECONDS", 30, 1)
+
+    // Include the MET API in /readyz (reported only; an outage there should not
+    // take every instance out of the load balancer)
+    readyzCheckMET := strings.ToLower(getEnv("READYZ_CHECK_MET", "false")) == "true"
+
+    // Seconds to keep serving with /readyz failing after SIGTERM so load balancers
+    // stop routing new traffic before connections are drained
+    shutdownDrainDelay, err := strconv.Atoi(getEnv("SHUTDOWN_DRAIN_DELAY_SECONDS", "5"))
+    if err != nil || shutdownDrainDelay < 0 {
+        shutdownDrainDelay = 5
+    }
+
+    // Cache of resized artwork images (disk, postgres, none)
+    imageCache := strings.ToLower(getEnv("IMAGE_CACHE", "disk"))
+    imageCacheDir := getEnv("IMAGE_CACHE_DIR", filepath.Join(os.TempDir(), "met-image-cache"))
+
+    // Storage of uploaded files (local, s3). The local store is served by this
+    // server under /blobs, so its public URL must point back here.
+    blobStore := strings.ToLower(getEnv("BLOB_STORE", "local"))
+    blobLocalDir := getEnv("BLOB_LOCAL_DIR", "data/blobs")
+    blobPublicURL := getEnv("BLOB_PUBLIC_URL", fmt.Sprintf("http://localhost:%d/blobs", port))
+    coverMaxBytes, err := strconv.ParseInt(getEnv("COVER_MAX_BYTES", "10485760"), 10, 64)
+    if err != nil || coverMaxBytes <= 0 {
+        coverMaxBytes = 10 << 20
+    }
+
+    // Absolute URLs used in Open Graph tags and oEmbed responses
+    publicBaseURL := strings.TrimSuffix(getEnv("PUBLIC_BASE_URL", fmt.Sprintf("http://localhost:%d", port)), "/")
+    frontendURL := strings.TrimSuffix(getEnv("FRONTEND_URL", "http://localhost:5173"), "/")
+
+    // Trace exporter (none, stdout, otlp). The OTLP endpoint is read by the
+    // exporter itself from OTEL_EXPORTER_OTLP_ENDPOINT (default http://localhost:4318).
+    tracesExporter := strings.ToLower(getEnv("OTEL_TRACES_EXPORTER", "none"))
+    serviceName := getEnv("OTEL_SERVICE_NAME", "backend")
+
     return Config{
         Port:           port,
         AllowedOrigins: origins,
@@ -80,6 +223,41 @@ func Load() Config {
         DBName:         dbName,
         DBSSLMode:      dbSSLMode,
         DBMigrate:      dbMigrate,
+
+        CommentBannedWords: commentBannedWords,
+        TrashRetentionDays: trashRetentionDays,
+
+        MaxRequestBodyBytes: maxRequestBodyBytes,
+        TrustedProxies:      trustedProxies,
+
+        METMaxAttempts:            metMaxAttempts,
+        METAttemptTimeoutSeconds:  metAttemptTimeout,
+        METRateLimit:              metRateLimit,
+        METBreakerThreshold:       metBreakerThreshold,
+        METBreakerCooldownSeconds: metBreakerCooldown,
+
+        ImageCache:    imageCache,
+        ImageCacheDir: imageCacheDir,
+
+        BlobStore:         blobStore,
+        BlobLocalDir:      blobLocalDir,
+        BlobPublicURL:     blobPublicURL,
+        S3Endpoint:        getEnv("S3_ENDPOINT", ""),
+        S3Region:          getEnv("S3_REGION", "us-east-1"),
+        S3Bucket:          getEnv("S3_BUCKET", ""),
+        S3AccessKeyID:     getEnv("S3_ACCESS_KEY_ID", ""),
+        S3SecretAccessKey: getEnv("S3_SECRET_ACCESS_KEY", ""),
+        S3PublicURL:       getEnv("S3_PUBLIC_URL", ""),
+        CoverMaxBytes:     coverMaxBytes,
+
+        PublicBaseURL: publicBaseURL,
+        FrontendURL:   frontendURL,
+
+        ReadyzCheckMET:     readyzCheckMET,
+        ShutdownDrainDelay: shutdownDrainDelay,
+
+        TracesExporter: tracesExporter,
+        ServiceName:    serviceName,
     }
 }
 
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// フィードに載せるアクティビティの種類
+type ActivityType string
+
+const (
+	ActivityMuseumCreated   ActivityType = "museum_created"
+	ActivityArtworkAdded    ActivityType = "artwork_added"
+	ActivityMuseumPublished ActivityType = "museum_published"
+)
+
+// Activity はユーザーの行動履歴（フィードの元データ）
+type Activity struct {
+	ID        int          `json:"id"`
+	ActorID   int          `json:"actorId"`
+	Type      ActivityType `json:"type"`
+	MuseumID  int          `json:"museumId"`
+	ObjectID  *int         `json:"objectId,omitempty"` // artwork_addedの場合のみ
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+// FeedItem represents an activity enriched for display in the feed.
+type FeedItem struct {
+	ID         int          `json:"id"`
+	Type       ActivityType `json:"type"`
+	Actor      UserSummary  `json:"actor"`
+	MuseumID   int          `json:"museumId"`
+	MuseumName string       `json:"museumName"`
+	ObjectID   *int         `json:"objectId,omitempty"`
+	CreatedAt  time.Time    `json:"createdAt"`
+}
+
+// FeedResponse represents a page of the personalized activity feed.
+// NextCursor を次回の before に指定すると続きを取得できる（末尾ではnil）
+type FeedResponse struct {
+	Items      []FeedItem `json:"items"`
+	NextCursor *int       `json:"nextCursor"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Comment はミュージアムへのコメント（返信を含む）
+type Comment struct {
+	ID        int        `json:"id"`
+	MuseumID  int        `json:"museumId"`
+	UserID    int        `json:"userId"`
+	ParentID  *int       `json:"parentId,omitempty"` // 返信先。トップレベルのコメントはnil
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+// CommentCreateRequest represents the request payload for posting a comment or reply.
+type CommentCreateRequest struct {
+	Body     string `json:"body"`
+	ParentID *int   `json:"parentId,omitempty"`
+}
+
+// CommentUpdateRequest represents the request payload for editing a comment.
+type CommentUpdateRequest struct {
+	Body string `json:"body"`
+}
+
+// CommentResponse represents a comment with its nested replies.
+type CommentResponse struct {
+	ID        int               `json:"id"`
+	MuseumID  int               `json:"museumId"`
+	UserID    int               `json:"userId"`
+	ParentID  *int              `json:"parentId,omitempty"`
+	Body      string            `json:"body"`
+	Deleted   bool              `json:"deleted"`
+	Edited    bool              `json:"edited"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Replies   []CommentResponse `json:"replies"`
+}
+
+// CommentPageResponse represents a paginated page of top-level comment threads.
+type CommentPageResponse struct {
+	Total    int               `json:"total"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+	Comments []CommentResponse `json:"comments"`
+}
+
+// IsDeleted returns true if the comment has been deleted.
+func (c Comment) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// ToResponse converts Comment to CommentResponse.
+// 削除済みコメントはスレッドを保つために残し、本文とユーザーを伏せる
+func (c Comment) ToResponse() CommentResponse {
+	res := CommentResponse{
+		ID:        c.ID,
+		MuseumID:  c.MuseumID,
+		UserID:    c.UserID,
+		ParentID:  c.ParentID,
+		Body:      c.Body,
+		Edited:    c.UpdatedAt.After(c.CreatedAt),
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Replies:   []CommentResponse{},
+	}
+	if c.IsDeleted() {
+		res.UserID = 0
+		res.Body = ""
+		res.Deleted = true
+	}
+	return res
+}
@@ -0,0 +1,32 @@
+package domain
+
+// MuseumCard は共有ページ（/m/{id}）のOpen Graph・Twitterカードに載せるミュージアムの情報
+type MuseumCard struct {
+	MuseumID    int
+	Title       string
+	Description string
+	PageURL     string // 共有ページ自身のURL（og:url）
+	AppURL      string // フロントエンドの閲覧画面
+	OEmbedURL   string // oEmbedの取得先（<link rel="alternate">）
+	ImageURL    string // og:image（アップロードしたカバー画像、なければ部屋の合成画像）
+	ImageWidth  int    // 0なら不明（カバー画像）
+	ImageHeight int
+	RenderURL   string // 部屋の合成画像（サイズが決まっているため埋め込みに使う）
+	Indexable   bool   // 公開ミュージアムだけを検索エンジンに載せる
+}
+
+// OEmbedResponse はoEmbed（https://oembed.com/）のrich形式のレスポンス
+type OEmbedResponse struct {
+	Version         string `json:"version"`
+	Type            string `json:"type"`
+	Title           string `json:"title"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ThumbnailWidth  int    `json:"thumbnail_width"`
+	ThumbnailHeight int    `json:"thumbnail_height"`
+	CacheAge        int    `json:"cache_age,omitempty"`
+}
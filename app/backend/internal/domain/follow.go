@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// UserSummary はフォロー一覧やフィードで使うユーザーの公開情報
+type UserSummary struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// FollowUser represents a user in a followers/following list.
+type FollowUser struct {
+	User       UserSummary `json:"user"`
+	FollowedAt time.Time   `json:"followedAt"`
+}
+
+// FollowListResponse represents a paginated followers/following list.
+type FollowListResponse struct {
+	UserID int          `json:"userId"`
+	Total  int          `json:"total"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+	Users  []FollowUser `json:"users"`
+}
+
+// FollowResponse represents the response payload for follow/unfollow operations.
+type FollowResponse struct {
+	UserID        int  `json:"userId"`
+	Following     bool `json:"following"`
+	FollowerCount int  `json:"followerCount"`
+}
@@ -6,10 +6,20 @@ import "time"
 type VisibilityType string
 
 const (
-    VisibilityPublic  VisibilityType = "public"
-    VisibilityPrivate VisibilityType = "private"
+    VisibilityPublic   VisibilityType = "public"
+    VisibilityPrivate  VisibilityType = "private"
+    VisibilityUnlisted VisibilityType = "unlisted" // リンクを知っていれば閲覧できるが一覧・検索には出さない
 )
 
+// IsValid returns true if the visibility is one of the known values.
+func (v VisibilityType) IsValid() bool {
+    switch v {
+    case VisibilityPublic, VisibilityPrivate, VisibilityUnlisted:
+        return true
+    }
+    return false
+}
+
 // Museumのデータベース
 type Museum struct {
     ID          int            `json:"id"`
@@ -19,20 +29,37 @@ type Museum struct {
     Visibility  VisibilityType `json:"visibility"`
     ImageURL    string         `json:"imageUrl"`
     CreatedAt   time.Time      `json:"createdAt"`
+    ForkedFrom  *int           `json:"forkedFrom,omitempty"` // 複製元のミュージアムID
+    DeletedAt   *time.Time     `json:"deletedAt,omitempty"`  // ゴミ箱に移した日時
+    Version     int            `json:"version"`              // 更新ごとに加算される（ETag）
+    UpdatedAt   time.Time      `json:"updatedAt"`
 }
 
 // MuseumCreateRequest represents the request payload for creating a museum.
+// validateタグの文字数上限はmuseumsテーブルのカラム長（name VARCHAR(200)、image_url VARCHAR(500)）に合わせる
+// visibilityを省略した場合はprivateとして作成する
 type MuseumCreateRequest struct {
-    UserID      int            `json:"userId"`
-    Name        string         `json:"name"`
+    UserID      int            `json:"userId" validate:"required,min=1"`
+    Name        string         `json:"name" validate:"required,max=200"`
     Description string         `json:"description"`
-    Visibility  VisibilityType `json:"visibility"`
-    ImageURL    string         `json:"imageUrl"`
+    Visibility  VisibilityType `json:"visibility" validate:"oneof=public private unlisted"`
+    ImageURL    string         `json:"imageUrl" validate:"max=500,url"`
 }
 
 // MuseumTitleUpdateRequest represents the request payload for updating museum title.
 type MuseumTitleUpdateRequest struct {
-    Title string `json:"title"`
+    Title string `json:"title" validate:"required,max=200"`
+}
+
+// MuseumCoverResponse represents the result of uploading a museum cover image.
+type MuseumCoverResponse struct {
+    ImageURL string `json:"imageUrl"`
+    Version  int    `json:"version"`
+}
+
+// MuseumVisibilityUpdateRequest represents the request payload for changing museum visibility.
+type MuseumVisibilityUpdateRequest struct {
+    Visibility VisibilityType `json:"visibility" validate:"required,oneof=public private unlisted"`
 }
 
 // ArtworkSearchQuery represents search parameters for artwork search.
@@ -41,14 +68,25 @@ type ArtworkSearchQuery struct {
     ObjectDate  string `json:"objectDate,omitempty"`
     City        string `json:"city,omitempty"`
     Medium      string `json:"medium,omitempty"`
+    // Expand に palette を含めると、計算済みの作品の代表色をレスポンスに付け加える
+    Expand []string `json:"expand,omitempty"`
+}
+
+// MuseumSearchResponse represents a ranked, paginated page of museum search results.
+type MuseumSearchResponse struct {
+    Query   string           `json:"query"`
+    Total   int              `json:"total"`
+    Limit   int              `json:"limit"`
+    Offset  int              `json:"offset"`
+    Museums []MuseumResponse `json:"museums"`
 }
 
 // MuseumUpdateRequest represents the request payload for updating a museum.
 type MuseumUpdateRequest struct {
-    Name        *string         `json:"name,omitempty"`        // ポインタで部分更新対応
+    Name        *string         `json:"name,omitempty" validate:"max=200"` // ポインタで部分更新対応
     Description *string         `json:"description,omitempty"`
-    Visibility  *VisibilityType `json:"visibility,omitempty"`
-    ImageURL    *string         `json:"imageUrl,omitempty"`
+    Visibility  *VisibilityType `json:"visibility,omitempty" validate:"oneof=public private unlisted"`
+    ImageURL    *string         `json:"imageUrl,omitempty" validate:"max=500,url"`
 }
 
 // MuseumResponse represents the response payload for museum data.
@@ -60,6 +98,23 @@ type MuseumResponse struct {
     Visibility  VisibilityType `json:"visibility"`
     ImageURL    string         `json:"imageUrl"`
     CreatedAt   time.Time      `json:"createdAt"`
+    ForkedFrom  *int           `json:"forkedFrom,omitempty"`
+    DeletedAt   *time.Time     `json:"deletedAt,omitempty"`
+    Version     int            `json:"version"`
+    UpdatedAt   time.Time      `json:"updatedAt"`
+    LikeCount   int            `json:"likeCount"`
+    ViewCount   int            `json:"viewCount"`
+    ForkCount   int            `json:"forkCount"`
+    LikedByMe   bool           `json:"likedByMe"`
+}
+
+// WithStats returns a copy of the response with engagement counters applied.
+func (r MuseumResponse) WithStats(st MuseumStats) MuseumResponse {
+    r.LikeCount = st.LikeCount
+    r.ViewCount = st.ViewCount
+    r.ForkCount = st.ForkCount
+    r.LikedByMe = st.LikedByMe
+    return r
 }
 
 // ToResponse converts Museum to MuseumResponse.
@@ -72,6 +127,10 @@ func (m Museum) ToResponse() MuseumResponse {
         Visibility:  m.Visibility,
         ImageURL:    m.ImageURL,
         CreatedAt:   m.CreatedAt,
+        ForkedFrom:  m.ForkedFrom,
+        DeletedAt:   m.DeletedAt,
+        Version:     m.Version,
+        UpdatedAt:   m.UpdatedAt,
     }
 }
 
@@ -80,6 +139,12 @@ func (m Museum) IsPublic() bool {
     return m.Visibility == VisibilityPublic
 }
 
+// IsViewableBy returns true if the user can open the museum without a share link.
+// 公開・限定公開は誰でも、非公開は所有者のみ閲覧できる
+func (m Museum) IsViewableBy(userID int) bool {
+    return m.Visibility != VisibilityPrivate || m.IsOwnedBy(userID)
+}
+
 // IsOwnedBy returns true if the museum is owned by the specified user.
 func (m Museum) IsOwnedBy(userID int) bool {
     return m.UserID == userID
@@ -0,0 +1,16 @@
+package domain
+
+// MuseumStats はミュージアムのエンゲージメント（いいね・閲覧数・フォーク数）の集計
+type MuseumStats struct {
+	LikeCount int  `json:"likeCount"`
+	ViewCount int  `json:"viewCount"`
+	ForkCount int  `json:"forkCount"`
+	LikedByMe bool `json:"likedByMe"`
+}
+
+// MuseumLikeResponse represents the response payload for like/unlike operations.
+type MuseumLikeResponse struct {
+	MuseumID  int  `json:"museumId"`
+	LikeCount int  `json:"likeCount"`
+	LikedByMe bool `json:"likedByMe"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// LayoutPlacement は壁に掛ける作品と、作品を収める枠の位置
+// 位置・大きさは背景に対する%（フロントエンドのCanvasMergeのleft・top・width・heightと同じ）
+type LayoutPlacement struct {
+	ObjectID int `json:"objectId" validate:"required,min=1"`
+	X        int `json:"x" validate:"min=0,max=100"`
+	Y        int `json:"y" validate:"min=0,max=100"`
+	Width    int `json:"width" validate:"required,min=1,max=100"`
+	Height   int `json:"height" validate:"required,min=1,max=100"`
+}
+
+// MuseumLayout はミュージアムの部屋の背景と作品の配置
+// 保存していないミュージアムは、展示作品を先頭から既定の枠に並べたもの（IsDefault）を返す
+type MuseumLayout struct {
+	MuseumID   int               `json:"museumId"`
+	Background string            `json:"background"`
+	Placements []LayoutPlacement `json:"placements"`
+	IsDefault  bool              `json:"isDefault"`
+	UpdatedAt  *time.Time        `json:"updatedAt,omitempty"`
+}
+
+// MuseumLayoutUpdateRequest represents the request payload for replacing a museum's layout.
+type MuseumLayoutUpdateRequest struct {
+	Background string            `json:"background" validate:"required,oneof=museum-back-1 museum-back-2"`
+	Placements []LayoutPlacement `json:"placements"`
+}
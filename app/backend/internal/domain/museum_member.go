@@ -0,0 +1,90 @@
+package domain
+
+import "time"
+
+// ミュージアムに対するユーザーの役割
+type MuseumRole string
+
+const (
+	RoleOwner  MuseumRole = "owner"  // 公開設定・共有・メンバー管理を含むすべての操作
+	RoleEditor MuseumRole = "editor" // タイトル変更や作品の追加などの編集
+	RoleViewer MuseumRole = "viewer" // 非公開ミュージアムの閲覧のみ
+)
+
+// ミュージアムに対する操作の種類
+type MuseumPermission int
+
+const (
+	PermissionView MuseumPermission = iota
+	PermissionEdit
+	PermissionManage
+)
+
+// IsValid returns true if the role is one of the known values.
+func (r MuseumRole) IsValid() bool {
+	switch r {
+	case RoleOwner, RoleEditor, RoleViewer:
+		return true
+	}
+	return false
+}
+
+// Can returns true if the role grants the permission.
+func (r MuseumRole) Can(p MuseumPermission) bool {
+	switch r {
+	case RoleOwner:
+		return true
+	case RoleEditor:
+		return p == PermissionView || p == PermissionEdit
+	case RoleViewer:
+		return p == PermissionView
+	}
+	return false
+}
+
+// MuseumMember represents a co-curator of a museum.
+// museums.user_id の作成者は常に owner として扱い、このテーブルには含めない
+type MuseumMember struct {
+	MuseumID  int         `json:"museumId"`
+	User      UserSummary `json:"user"`
+	Role      MuseumRole  `json:"role"`
+	IsCreator bool        `json:"isCreator"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// MuseumMemberUpdateRequest represents the request payload for changing a member's role.
+type MuseumMemberUpdateRequest struct {
+	Role MuseumRole `json:"role"`
+}
+
+// 招待の状態
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+)
+
+// MuseumInvitation はミュージアムの共同キュレーターへの招待
+// ユーザーIDかメールアドレスのどちらかで招待する
+type MuseumInvitation struct {
+	ID            int              `json:"id"`
+	MuseumID      int              `json:"museumId"`
+	MuseumName    string           `json:"museumName"`
+	InvitedBy     int              `json:"invitedBy"`
+	InviteeUserID *int             `json:"inviteeUserId,omitempty"`
+	InviteeEmail  string           `json:"inviteeEmail,omitempty"`
+	Role          MuseumRole       `json:"role"`
+	Status        InvitationStatus `json:"status"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	RespondedAt   *time.Time       `json:"respondedAt,omitempty"`
+}
+
+// MuseumInvitationCreateRequest represents the request payload for inviting a co-curator.
+type MuseumInvitationCreateRequest struct {
+	UserID *int       `json:"userId,omitempty"`
+	Email  string     `json:"email,omitempty"`
+	Role   MuseumRole `json:"role"`
+}
@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"reflect"
+	"time"
+)
+
+// リビジョンを作成した操作の種類
+type RevisionAction string
+
+const (
+	RevisionCreate           RevisionAction = "create"
+	RevisionUpdateTitle      RevisionAction = "update_title"
+	RevisionUpdateVisibility RevisionAction = "update_visibility"
+	RevisionUpdateCover      RevisionAction = "update_cover"
+	RevisionUpdateLayout     RevisionAction = "update_layout"
+	RevisionAddArtwork       RevisionAction = "add_artwork"
+	RevisionRemoveArtwork    RevisionAction = "remove_artwork"
+	RevisionRestoreArtwork   RevisionAction = "restore_artwork"
+	RevisionFork             RevisionAction = "fork"
+	RevisionRestore          RevisionAction = "restore"
+)
+
+// MuseumSnapshot はある時点のミュージアムのメタデータと展示作品
+type MuseumSnapshot struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Visibility  VisibilityType    `json:"visibility"`
+	ImageURL    string            `json:"imageUrl"`
+	Artworks    []SnapshotArtwork `json:"artworks"`
+	Layout      *SnapshotLayout   `json:"layout,omitempty"` // 配置を保存していない（または記録する前の）リビジョンではnil
+}
+
+// SnapshotLayout はスナップショット内の部屋の背景と作品の配置
+type SnapshotLayout struct {
+	Background string            `json:"background"`
+	Placements []LayoutPlacement `json:"placements"`
+}
+
+// SnapshotArtwork はスナップショット内の展示作品（展示順）
+type SnapshotArtwork struct {
+	ObjectID    int    `json:"objectId"`
+	Description string `json:"description"`
+}
+
+// MuseumRevision はミュージアムの変更履歴の1件
+type MuseumRevision struct {
+	ID           int            `json:"id"`
+	MuseumID     int            `json:"museumId"`
+	Author       *UserSummary   `json:"author"` // ユーザー削除後はnil
+	Action       RevisionAction `json:"action"`
+	RestoredFrom *int           `json:"restoredFrom,omitempty"` // restoreの場合の復元元リビジョン
+	CreatedAt    time.Time      `json:"createdAt"`
+	Snapshot     MuseumSnapshot `json:"snapshot"`
+}
+
+// MuseumRevisionSummary is a revision without its snapshot, used in listings.
+type MuseumRevisionSummary struct {
+	ID           int            `json:"id"`
+	MuseumID     int            `json:"museumId"`
+	Author       *UserSummary   `json:"author"`
+	Action       RevisionAction `json:"action"`
+	RestoredFrom *int           `json:"restoredFrom,omitempty"`
+	ArtworkCount int            `json:"artworkCount"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+// Summary returns the revision without its snapshot.
+func (r MuseumRevision) Summary() MuseumRevisionSummary {
+	return MuseumRevisionSummary{
+		ID:           r.ID,
+		MuseumID:     r.MuseumID,
+		Author:       r.Author,
+		Action:       r.Action,
+		RestoredFrom: r.RestoredFrom,
+		ArtworkCount: len(r.Snapshot.Artworks),
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// MuseumRevisionPageResponse represents a paginated list of revisions (newest first).
+type MuseumRevisionPageResponse struct {
+	Total     int                     `json:"total"`
+	Limit     int                     `json:"limit"`
+	Offset    int                     `json:"offset"`
+	Revisions []MuseumRevisionSummary `json:"revisions"`
+}
+
+// FieldChange はメタデータ1項目の変更
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ArtworkChange は両方のリビジョンに含まれる作品の説明文・展示位置の変更
+type ArtworkChange struct {
+	ObjectID        int    `json:"objectId"`
+	FromDescription string `json:"fromDescription"`
+	ToDescription   string `json:"toDescription"`
+	FromPosition    int    `json:"fromPosition"`
+	ToPosition      int    `json:"toPosition"`
+}
+
+// LayoutChange は部屋の配置の変更（配置のないリビジョン側はnil）
+type LayoutChange struct {
+	From *SnapshotLayout `json:"from"`
+	To   *SnapshotLayout `json:"to"`
+}
+
+// MuseumRevisionDiff は2つのリビジョン間の差分
+type MuseumRevisionDiff struct {
+	From            int               `json:"from"`
+	To              int               `json:"to"`
+	Fields          []FieldChange     `json:"fields"`
+	AddedArtworks   []SnapshotArtwork `json:"addedArtworks"`
+	RemovedArtworks []SnapshotArtwork `json:"removedArtworks"`
+	ChangedArtworks []ArtworkChange   `json:"changedArtworks"`
+	Layout          *LayoutChange     `json:"layout,omitempty"` // 配置が変わっていなければ省略
+}
+
+// DiffSnapshots returns the changes needed to go from snapshot a to snapshot b.
+// From・Toのリビジョンは呼び出し側で設定する
+// 展示位置（position）は両方に含まれる作品だけで数えた0始まりの展示順で、
+// 作品の追加・削除だけでは並び替えとして扱わない
+func DiffSnapshots(a, b MuseumSnapshot) MuseumRevisionDiff {
+	fields := []FieldChange{}
+	for _, f := range []FieldChange{
+		{Field: "name", From: a.Name, To: b.Name},
+		{Field: "description", From: a.Description, To: b.Description},
+		{Field: "visibility", From: string(a.Visibility), To: string(b.Visibility)},
+		{Field: "imageUrl", From: a.ImageURL, To: b.ImageURL},
+	} {
+		if f.From != f.To {
+			fields = append(fields, f)
+		}
+	}
+
+	inA := make(map[int]bool, len(a.Artworks))
+	for _, art := range a.Artworks {
+		inA[art.ObjectID] = true
+	}
+	inB := make(map[int]bool, len(b.Artworks))
+	for _, art := range b.Artworks {
+		inB[art.ObjectID] = true
+	}
+
+	// 両方に含まれる作品の、変更前の位置と内容
+	type placed struct {
+		pos int
+		art SnapshotArtwork
+	}
+	prev := make(map[int]placed)
+	removed := []SnapshotArtwork{}
+	for _, art := range a.Artworks {
+		if !inB[art.ObjectID] {
+			removed = append(removed, art)
+			continue
+		}
+		prev[art.ObjectID] = placed{pos: len(prev), art: art}
+	}
+
+	added := []SnapshotArtwork{}
+	changed := []ArtworkChange{}
+	pos := 0
+	for _, art := range b.Artworks {
+		if !inA[art.ObjectID] {
+			added = append(added, art)
+			continue
+		}
+		p := prev[art.ObjectID]
+		if p.art.Description != art.Description || p.pos != pos {
+			changed = append(changed, ArtworkChange{
+				ObjectID:        art.ObjectID,
+				FromDescription: p.art.Description,
+				ToDescription:   art.Description,
+				FromPosition:    p.pos,
+				ToPosition:      pos,
+			})
+		}
+		pos++
+	}
+
+	diff := MuseumRevisionDiff{Fields: fields, AddedArtworks: added, RemovedArtworks: removed, ChangedArtworks: changed}
+	if !reflect.DeepEqual(a.Layout, b.Layout) {
+		diff.Layout = &LayoutChange{From: a.Layout, To: b.Layout}
+	}
+	return diff
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	a := MuseumSnapshot{
+		Name: "印象派", Description: "光", Visibility: VisibilityPrivate, ImageURL: "a.jpg",
+		Artworks: []SnapshotArtwork{
+			{ObjectID: 1, Description: "睡蓮"},
+			{ObjectID: 2, Description: "糸杉"},
+			{ObjectID: 3, Description: "ひまわり"},
+			{ObjectID: 4, Description: "星月夜"},
+		},
+	}
+	b := MuseumSnapshot{
+		Name: "印象派と後期印象派", Description: "光", Visibility: VisibilityPublic, ImageURL: "a.jpg",
+		Artworks: []SnapshotArtwork{
+			{ObjectID: 3, Description: "ひまわり"},
+			{ObjectID: 5, Description: "追加"},
+			{ObjectID: 1, Description: "睡蓮の池"},
+			{ObjectID: 4, Description: "星月夜"},
+		},
+	}
+
+	diff := DiffSnapshots(a, b)
+	fields, added, removed, changed := diff.Fields, diff.AddedArtworks, diff.RemovedArtworks, diff.ChangedArtworks
+
+	wantFields := []FieldChange{
+		{Field: "name", From: "印象派", To: "印象派と後期印象派"},
+		{Field: "visibility", From: "private", To: "public"},
+	}
+	if !reflect.DeepEqual(fields, wantFields) {
+		t.Errorf("fields = %+v, want %+v", fields, wantFields)
+	}
+	if want := []SnapshotArtwork{{ObjectID: 5, Description: "追加"}}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %+v, want %+v", added, want)
+	}
+	if want := []SnapshotArtwork{{ObjectID: 2, Description: "糸杉"}}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %+v, want %+v", removed, want)
+	}
+	// 2の削除と5の追加だけでは4の位置は変わらない（共通の作品1, 3, 4のうちで数える）
+	wantChanged := []ArtworkChange{
+		{ObjectID: 3, FromDescription: "ひまわり", ToDescription: "ひまわり", FromPosition: 1, ToPosition: 0},
+		{ObjectID: 1, FromDescription: "睡蓮", ToDescription: "睡蓮の池", FromPosition: 0, ToPosition: 1},
+	}
+	if !reflect.DeepEqual(changed, wantChanged) {
+		t.Errorf("changed = %+v, want %+v", changed, wantChanged)
+	}
+	if diff.Layout != nil {
+		t.Errorf("layout = %+v, want nil", diff.Layout)
+	}
+}
+
+func TestDiffSnapshotsUnchanged(t *testing.T) {
+	s := MuseumSnapshot{Name: "x", Artworks: []SnapshotArtwork{{ObjectID: 1}, {ObjectID: 2}}}
+	diff := DiffSnapshots(s, s)
+	fields, added, removed, changed := diff.Fields, diff.AddedArtworks, diff.RemovedArtworks, diff.ChangedArtworks
+	// 空の場合もJSONでnullではなく[]になるよう、nilではなく空のスライスを返す
+	if fields == nil || added == nil || removed == nil || changed == nil {
+		t.Fatal("expected empty slices, not nil")
+	}
+	if len(fields)+len(added)+len(removed)+len(changed) != 0 {
+		t.Errorf("expected no changes, got %+v %+v %+v %+v", fields, added, removed, changed)
+	}
+}
+
+func TestDiffSnapshotsLayout(t *testing.T) {
+	layout := func(x int) *SnapshotLayout {
+		return &SnapshotLayout{Background: "white", Placements: []LayoutPlacement{{ObjectID: 1, X: x, Y: 0, Width: 30, Height: 20}}}
+	}
+	a := MuseumSnapshot{Name: "x", Artworks: []SnapshotArtwork{{ObjectID: 1}}}
+
+	b := a
+	b.Layout = layout(10)
+	if diff := DiffSnapshots(a, b); diff.Layout == nil || diff.Layout.From != nil || !reflect.DeepEqual(diff.Layout.To, b.Layout) {
+		t.Errorf("saving a layout: got %+v", diff.Layout)
+	}
+
+	c := b
+	c.Layout = layout(20)
+	if diff := DiffSnapshots(b, c); diff.Layout == nil || !reflect.DeepEqual(diff.Layout.From, b.Layout) || !reflect.DeepEqual(diff.Layout.To, c.Layout) {
+		t.Errorf("moving an artwork: got %+v", diff.Layout)
+	}
+
+	d := b
+	d.Layout = layout(10)
+	if diff := DiffSnapshots(b, d); diff.Layout != nil {
+		t.Errorf("same layout: got %+v, want nil", diff.Layout)
+	}
+}
@@ -36,6 +36,8 @@ type ArtworkInMuseum struct {
     ObjectID          int       `json:"objectId"`
     Description       string    `json:"description"`
     AddedAt          time.Time `json:"addedAt"`
+    // 作品画像の代表色（未計算の場合は省略し、バックグラウンドで計算する）
+    Palette *ArtworkPalette `json:"palette,omitempty"`
     // 一応？)将来的に外部APIから取得した作品情報も含める可能性
     // Title         string    `json:"title,omitempty"`
     // Artist        string    `json:"artist,omitempty"`
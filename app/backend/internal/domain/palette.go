@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// PaletteColor は作品画像の代表色の1つ
+type PaletteColor struct {
+	Hex   string  `json:"hex"`   // #rrggbb
+	Ratio float64 `json:"ratio"` // 画像に占める割合（0〜1）
+}
+
+// ArtworkPalette は作品画像の代表色と平均の明るさ
+// 額縁や壁の色を自動で選ぶのに使う。画像のない作品はColorsが空になる
+type ArtworkPalette struct {
+	ObjectID   int            `json:"objectId"`
+	Colors     []PaletteColor `json:"colors"`     // 割合の大きい順
+	Brightness float64        `json:"brightness"` // 平均の相対輝度（0〜1）
+	ComputedAt time.Time      `json:"computedAt"`
+}
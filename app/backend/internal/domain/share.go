@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// 共有リンクで許可する操作の範囲
+type ShareScope string
+
+const (
+	ShareScopeView ShareScope = "view" // 閲覧のみ
+)
+
+// ShareToken は非公開ミュージアムを共有するためのリンク（トークン）
+type ShareToken struct {
+	ID        int        `json:"id"`
+	MuseumID  int        `json:"museumId"`
+	TokenHash string     `json:"-"` // トークン自体は保存せずハッシュのみ保持する
+	Scope     ShareScope `json:"scope"`
+	CreatedBy int        `json:"createdBy"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// ShareTokenCreateRequest represents the request payload for creating a share link.
+type ShareTokenCreateRequest struct {
+	ExpiresInHours *int       `json:"expiresInHours,omitempty"` // 省略時は無期限
+	Scope          ShareScope `json:"scope,omitempty"`          // 省略時は view
+}
+
+// ShareTokenResponse represents a share link.
+// Token は作成直後のレスポンスにのみ含まれる（以降は取得できない）
+type ShareTokenResponse struct {
+	ID        int        `json:"id"`
+	MuseumID  int        `json:"museumId"`
+	Token     string     `json:"token,omitempty"`
+	Scope     ShareScope `json:"scope"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Revoked   bool       `json:"revoked"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// SharedMuseumResponse represents a museum opened through a share link.
+type SharedMuseumResponse struct {
+	Museum   MuseumResponse    `json:"museum"`
+	Artworks []ArtworkInMuseum `json:"artworks"`
+	Scope    ShareScope        `json:"scope"`
+}
+
+// IsActive returns true if the token is neither revoked nor expired at the given time.
+func (t ShareToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+// ToResponse converts ShareToken to ShareTokenResponse (without the raw token).
+func (t ShareToken) ToResponse(now time.Time) ShareTokenResponse {
+	return ShareTokenResponse{
+		ID:        t.ID,
+		MuseumID:  t.MuseumID,
+		Scope:     t.Scope,
+		ExpiresAt: t.ExpiresAt,
+		Revoked:   t.RevokedAt != nil,
+		Active:    t.IsActive(now),
+		CreatedAt: t.CreatedAt,
+	}
+}
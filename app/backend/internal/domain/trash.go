@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// TrashedArtwork はゴミ箱にある展示作品
+type TrashedArtwork struct {
+	MuseumID    int       `json:"museumId"`
+	MuseumName  string    `json:"museumName"`
+	ObjectID    int       `json:"objectId"`
+	Description string    `json:"description"`
+	DeletedAt   time.Time `json:"deletedAt"`
+}
+
+// TrashResponse represents the caller's trash.
+// RetentionDays を過ぎた項目は定期的に物理削除される
+type TrashResponse struct {
+	RetentionDays int              `json:"retentionDays"`
+	Museums       []MuseumResponse `json:"museums"`
+	Artworks      []TrashedArtwork `json:"artworks"`
+}
@@ -0,0 +1,136 @@
+// Package health は/livezと/readyzで返す死活・受付可否の確認を提供する
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout は1つの確認にかける時間の上限
+const checkTimeout = 2 * time.Second
+
+// 確認結果の状態
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Check はreadyzで実行する確認
+// Criticalがfalseの確認は失敗しても結果に載せるだけで、readyzを失敗にしない
+type Check struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) error
+}
+
+// CheckResult は1つの確認の結果
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report はreadyzのレスポンス
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Readiness はリクエストを受け付けられるかを判定する
+// シャットダウンの開始時にSetDrainingを呼ぶと、以降のreadyzは確認を実行せずに503を返す
+type Readiness struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+// NewReadiness は指定した確認を行うReadinessを作成する
+func NewReadiness(checks ...Check) *Readiness {
+	return &Readiness{checks: checks}
+}
+
+// SetDraining はシャットダウン中であることを記録する
+func (r *Readiness) SetDraining() {
+	r.draining.Store(true)
+}
+
+// Draining はシャットダウン中かを返す
+func (r *Readiness) Draining() bool {
+	return r.draining.Load()
+}
+
+// Check はすべての確認を並行して実行し、結果をまとめる
+func (r *Readiness) Check(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: map[string]CheckResult{}}
+	if r.Draining() {
+		report.Status = StatusDraining
+		return report
+	}
+
+	results := make([]CheckResult, len(r.checks))
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range r.checks {
+		report.Checks[c.Name] = results[i]
+		if c.Critical && results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// runCheck はタイムアウト付きで確認を1つ実行する
+func runCheck(ctx context.Context, c Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Run(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		Critical:  c.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// ServeHTTP はGET /readyzを処理する
+// すべての重要な確認が成功すれば200、1つでも失敗するかシャットダウン中なら503を返す
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	report := r.Check(req.Context())
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+// Live はGET /livezを処理する
+// プロセスが応答できることだけを示し、DBなどの依存先は確認しない
+func Live(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
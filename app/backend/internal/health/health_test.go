@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func ok(context.Context) error   { return nil }
+func fail(context.Context) error { return errors.New("connection refused") }
+
+func serveReadyz(t *testing.T, r *Readiness) (int, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var report Report
+	if err := json.NewDecoder(rec.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	return rec.Code, report
+}
+
+func TestReadinessReportsEachCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		checks     []Check
+		wantCode   int
+		wantStatus string
+	}{
+		{
+			name:       "all ok",
+			checks:     []Check{{Name: "database", Critical: true, Run: ok}},
+			wantCode:   http.StatusOK,
+			wantStatus: StatusOK,
+		},
+		{
+			name:       "critical failure",
+			checks:     []Check{{Name: "database", Critical: true, Run: fail}, {Name: "met", Run: ok}},
+			wantCode:   http.StatusServiceUnavailable,
+			wantStatus: StatusFail,
+		},
+		{
+			name:       "non-critical failure",
+			checks:     []Check{{Name: "database", Critical: true, Run: ok}, {Name: "met", Run: fail}},
+			wantCode:   http.StatusOK,
+			wantStatus: StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, report := serveReadyz(t, NewReadiness(tt.checks...))
+			if code != tt.wantCode || report.Status != tt.wantStatus {
+				t.Fatalf("expected %d %q, got %d %q", tt.wantCode, tt.wantStatus, code, report.Status)
+			}
+			if len(report.Checks) != len(tt.checks) {
+				t.Fatalf("expected %d checks, got %v", len(tt.checks), report.Checks)
+			}
+			for _, c := range tt.checks {
+				res := report.Checks[c.Name]
+				if (c.Run(context.Background()) == nil) != (res.Status == StatusOK) {
+					t.Errorf("unexpected result for %s: %+v", c.Name, res)
+				}
+				if res.Status == StatusFail && res.Error == "" {
+					t.Errorf("expected error message for %s", c.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessFailsWhileDraining(t *testing.T) {
+	called := false
+	r := NewReadiness(Check{Name: "database", Critical: true, Run: func(context.Context) error {
+		called = true
+		return nil
+	}})
+	r.SetDraining()
+
+	code, report := serveReadyz(t, r)
+	if code != http.StatusServiceUnavailable || report.Status != StatusDraining {
+		t.Fatalf("expected 503 draining, got %d %q", code, report.Status)
+	}
+	if called {
+		t.Fatal("checks should not run while draining")
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type ArtworkHandler struct {
+	log        *slog.Logger
+	artworkSvc *service.ArtworkService
+	shareSvc   *service.ShareService
+}
+
+func NewArtworkHandler(log *slog.Logger, artworkSvc *service.ArtworkService, shareSvc *service.ShareService) *ArtworkHandler {
+	return &ArtworkHandler{log: log, artworkSvc: artworkSvc, shareSvc: shareSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *ArtworkHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// List はミュージアムに展示されている作品を取得する
+// 共有リンクのトークンを指定すると非公開ミュージアムの作品も取得できる
+// GET /api/v1/museums/{id}/artworks?token={shareToken}
+func (h *ArtworkHandler) List(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	shared, err := resolveShareToken(r, h.shareSvc, museumID)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var artworks []domain.ArtworkInMuseum
+	if shared {
+		artworks, err = h.artworkSvc.ListSharedArtworks(r.Context(), museumID)
+	} else {
+		artworks, err = h.artworkSvc.ListArtworks(r.Context(), museumID, callerID)
+	}
+	if err != nil {
+		h.logError(r, "failed to list artworks", err, slog.Int("museumId", museumID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, artworks)
+}
+
+// Add はミュージアムに作品を追加する（所有者・編集者のみ）
+// POST /api/v1/museums/{id}/artworks
+func (h *ArtworkHandler) Add(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.MuseumToArtCreateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	artwork, err := h.artworkSvc.AddArtwork(r.Context(), museumID, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to add artwork", err, slog.Int("museumId", museumID), slog.Int("objectId", req.ObjectID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, artwork)
+}
+
+// Remove は展示作品をゴミ箱に移す（所有者・編集者のみ）
+// DELETE /api/v1/museums/{id}/artworks/{objectId}
+func (h *ArtworkHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	objectID, err := parsePositiveIntParam(r, "objectId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.artworkSvc.RemoveArtwork(r.Context(), museumID, objectID, callerID); err != nil {
+		h.logError(r, "failed to remove artwork", err, slog.Int("museumId", museumID), slog.Int("objectId", objectID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
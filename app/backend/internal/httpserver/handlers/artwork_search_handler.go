@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/metclient"
 	"backend/internal/service"
 )
 
@@ -18,13 +22,13 @@ func NewArtworkSearchHandler(log *slog.Logger, searchSvc *service.ArtworkSearchS
 	return &ArtworkSearchHandler{log: log, searchSvc: searchSvc}
 }
 
-// logError はエラーログを出力するヘルパーメソッド
-func (h *ArtworkSearchHandler) logError(message string, err error, attrs ...slog.Attr) {
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *ArtworkSearchHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
 	args := []any{slog.String("error", err.Error())}
 	for _, attr := range attrs {
 		args = append(args, attr)
 	}
-	h.log.Error(message, args...)
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
 }
 
 // SearchArtworks はMET APIを使用して作品を検索する
@@ -33,9 +37,13 @@ func (h *ArtworkSearchHandler) SearchArtworks(w http.ResponseWriter, r *http.Req
 	query := h.parseArtworkSearchQuery(r)
 	limit := parseOptionalIntQuery(r, "limit", 20)
 
-	result, err := h.searchSvc.SearchArtworks(query, limit)
+	result, err := h.searchSvc.SearchArtworks(r.Context(), query, limit)
 	if err != nil {
-		h.logError("failed to search artworks", err, slog.Any("query", query))
+		h.logError(r, "failed to search artworks", err, slog.Any("query", query))
+		if errors.Is(err, metclient.ErrCircuitOpen) {
+			HandleError(w, ErrMETUnavailable)
+			return
+		}
 		HandleError(w, NewInternalServerError("failed to search artworks"))
 		return
 	}
@@ -59,5 +67,12 @@ func (h *ArtworkSearchHandler) parseArtworkSearchQuery(r *http.Request) domain.A
 	query.City = r.URL.Query().Get("city")
 	query.Medium = r.URL.Query().Get("medium")
 
+	// 付け加える情報（カンマ区切り、例: expand=palette）
+	for _, e := range strings.Split(r.URL.Query().Get("expand"), ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			query.Expand = append(query.Expand, e)
+		}
+	}
+
 	return query
 }
\ No newline at end of file
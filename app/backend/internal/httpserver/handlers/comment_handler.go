@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type CommentHandler struct {
+	log        *slog.Logger
+	commentSvc *service.CommentService
+}
+
+func NewCommentHandler(log *slog.Logger, commentSvc *service.CommentService) *CommentHandler {
+	return &CommentHandler{log: log, commentSvc: commentSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *CommentHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// List はミュージアムのコメントをスレッド単位で取得する
+// GET /api/v1/museums/{id}/comments?limit=20&offset=0
+func (h *CommentHandler) List(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	limit := parseOptionalIntQuery(r, "limit", 20)
+	offset := parseOptionalIntQuery(r, "offset", 0)
+
+	page, err := h.commentSvc.ListComments(r.Context(), museumID, callerID, limit, offset)
+	if err != nil {
+		h.logError(r, "failed to list comments", err, slog.Int("museumId", museumID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// Create はコメントまたは返信を投稿する
+// POST /api/v1/museums/{id}/comments
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.CommentCreateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	comment, err := h.commentSvc.CreateComment(r.Context(), museumID, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to create comment", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, comment)
+}
+
+// Update はコメント本文を編集する（投稿者のみ）
+// PATCH /api/v1/comments/{commentId}
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "commentId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.CommentUpdateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	comment, err := h.commentSvc.UpdateComment(r.Context(), id, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to update comment", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, comment)
+}
+
+// Delete はコメントを削除する（投稿者またはミュージアムの所有者）
+// DELETE /api/v1/comments/{commentId}
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "commentId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.commentSvc.DeleteComment(r.Context(), id, callerID); err != nil {
+		h.logError(r, "failed to delete comment", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
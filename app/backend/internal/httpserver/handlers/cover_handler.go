@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+// coverFormOverhead はmultipartの境界やヘッダーの分として画像の上限に加えて許すバイト数
+const coverFormOverhead = 64 << 10
+
+type CoverHandler struct {
+	log      *slog.Logger
+	coverSvc *service.CoverService
+}
+
+func NewCoverHandler(log *slog.Logger, coverSvc *service.CoverService) *CoverHandler {
+	return &CoverHandler{log: log, coverSvc: coverSvc}
+}
+
+// Upload はミュージアムのカバー画像をアップロードし、image_urlを更新する（所有者・編集者のみ）
+// multipart/form-dataのfileフィールドで画像を送る。If-Matchに取得時のETagが必要で、他で更新されていれば412を返す
+// POST /api/v1/museums/{id}/cover
+func (h *CoverHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	// JSONのボディの上限は対象外なので、ここで画像の上限に合わせて制限する
+	r.Body = http.MaxBytesReader(w, r.Body, h.coverSvc.MaxBytes()+coverFormOverhead)
+	file, err := coverFilePart(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	cover, err := h.coverSvc.UpdateCover(r.Context(), id, callerID, file, version)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to update museum cover",
+			slog.String("error", err.Error()),
+			slog.Int("id", id),
+		)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			err = service.ErrCoverTooLarge
+		}
+		HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(cover.Version))
+	respondJSON(w, http.StatusOK, cover)
+}
+
+// coverFilePart はmultipartのボディからfileフィールドを探して返す（ファイル全体をメモリやディスクに展開しない）
+func coverFilePart(r *http.Request) (io.Reader, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, HTTPError{Code: http.StatusUnsupportedMediaType, Message: "Content-Type must be multipart/form-data"}
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, service.ErrCoverRequired
+		}
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, service.ErrCoverTooLarge
+			}
+			return nil, NewBadRequestError("invalid multipart body")
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
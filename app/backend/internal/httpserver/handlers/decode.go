@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DecodeJSONBody はリクエストボディを1つのJSONオブジェクトとしてdstに読み込む
+// ボディの上限はルーターのミドルウェア（http.MaxBytesReader）で設定する。以下はHTTPErrorを返す
+//   - Content-Typeがapplication/jsonでない: 415
+//   - ボディが上限を超えた: 413
+//   - 空のボディ、不正なJSON、型の合わないフィールド、未知のフィールド、2つ目以降のJSON値: 400
+func DecodeJSONBody(r *http.Request, dst any) error {
+	if err := checkJSONContentType(r); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+
+	// 1つのJSON値の後に続くデータは受け付けない
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return bodyTooLargeError(maxBytesErr.Limit)
+		}
+		return NewBadRequestError("request body must only contain a single JSON value")
+	}
+
+	return nil
+}
+
+// decodeJSONBody decodes JSON request body (internal use)
+func decodeJSONBody(r *http.Request, dst any) error {
+	return DecodeJSONBody(r, dst)
+}
+
+// checkJSONContentType はContent-Typeがapplication/json（charsetなどのパラメーターは可）かどうかを確認する
+func checkJSONContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return ErrUnsupportedMediaType
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return ErrUnsupportedMediaType
+	}
+	return nil
+}
+
+// decodeError はjson.Decoderのエラーをどこが誤っているか分かるメッセージのHTTPErrorに変換する
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return bodyTooLargeError(maxBytesErr.Limit)
+	case errors.Is(err, io.EOF):
+		return NewBadRequestError("request body must not be empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return NewBadRequestError("request body contains badly-formed JSON")
+	case errors.As(err, &syntaxErr):
+		return NewBadRequestError(fmt.Sprintf("request body contains badly-formed JSON (at position %d)", syntaxErr.Offset))
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return NewBadRequestError(fmt.Sprintf("request body must be a JSON object, not %s", typeErr.Value))
+		}
+		return NewBadRequestError(fmt.Sprintf("request body contains an invalid value for the %q field (expected %s, got %s)", typeErr.Field, typeErr.Type, typeErr.Value))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/jsonは未知のフィールドを専用のエラー型で返さないためメッセージから取り出す
+		field := strings.TrimPrefix(err.Error(), "json: unknown field ")
+		return NewBadRequestError("request body contains unknown field " + field)
+	}
+	return ErrInvalidRequestBody
+}
+
+// bodyTooLargeError はボディが上限を超えたときの413エラーを作成する
+func bodyTooLargeError(limit int64) HTTPError {
+	return HTTPError{Code: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("request body must not be larger than %d bytes", limit)}
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type decodeTarget struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func newJSONRequest(body, contentType string, limit int64) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if limit > 0 {
+		r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, limit)
+	}
+	return r
+}
+
+func TestDecodeJSONBody_OK(t *testing.T) {
+	var dst decodeTarget
+	r := newJSONRequest(`{"name":"a","count":2}`+"\n", "application/json; charset=utf-8", 0)
+	if err := DecodeJSONBody(r, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Name != "a" || dst.Count != 2 {
+		t.Fatalf("unexpected result: %+v", dst)
+	}
+}
+
+func TestDecodeJSONBody_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		limit       int64
+		wantCode    int
+		wantMessage string
+	}{
+		{"missing content type", `{}`, "", 0, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"wrong content type", `{}`, "text/plain", 0, http.StatusUnsupportedMediaType, "Content-Type must be application/json"},
+		{"too large", `{"name":"` + strings.Repeat("a", 64) + `"}`, "application/json", 16, http.StatusRequestEntityTooLarge, "request body must not be larger than 16 bytes"},
+		{"empty", ``, "application/json", 0, http.StatusBadRequest, "request body must not be empty"},
+		{"syntax error", `{"name":}`, "application/json", 0, http.StatusBadRequest, "request body contains badly-formed JSON (at position 9)"},
+		{"truncated", `{"name":"a"`, "application/json", 0, http.StatusBadRequest, "request body contains badly-formed JSON"},
+		{"wrong type", `{"count":"x"}`, "application/json", 0, http.StatusBadRequest, `request body contains an invalid value for the "count" field (expected int, got string)`},
+		{"not an object", `[1]`, "application/json", 0, http.StatusBadRequest, "request body must be a JSON object, not array"},
+		{"unknown field", `{"name":"a","extra":1}`, "application/json", 0, http.StatusBadRequest, `request body contains unknown field "extra"`},
+		{"multiple values", `{"name":"a"}{"name":"b"}`, "application/json", 0, http.StatusBadRequest, "request body must only contain a single JSON value"},
+		{"trailing garbage", `{"name":"a"} x`, "application/json", 0, http.StatusBadRequest, "request body must only contain a single JSON value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst decodeTarget
+			err := DecodeJSONBody(newJSONRequest(tt.body, tt.contentType, tt.limit), &dst)
+
+			var httpErr HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected HTTPError, got %v", err)
+			}
+			if httpErr.Code != tt.wantCode {
+				t.Errorf("expected status %d, got %d", tt.wantCode, httpErr.Code)
+			}
+			if httpErr.Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %q", tt.wantMessage, httpErr.Message)
+			}
+		})
+	}
+}
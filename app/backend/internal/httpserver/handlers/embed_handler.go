@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+// sharePageTemplate はクローラー向けのメタタグを持ち、ブラウザはフロントエンドの閲覧画面へ移動させるページ
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}">
+{{if not .Indexable}}<meta name="robots" content="noindex">
+{{end}}<meta property="og:type" content="website">
+<meta property="og:site_name" content="バーチャル美術館">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.PageURL}}">
+<meta property="og:image" content="{{.ImageURL}}">
+{{if .ImageWidth}}<meta property="og:image:width" content="{{.ImageWidth}}">
+<meta property="og:image:height" content="{{.ImageHeight}}">
+{{end}}<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<meta name="twitter:image" content="{{.ImageURL}}">
+<link rel="alternate" type="application/json+oembed" href="{{.OEmbedURL}}" title="{{.Title}}">
+<meta http-equiv="refresh" content="0; url={{.AppURL}}">
+</head>
+<body>
+<p><a href="{{.AppURL}}">{{.Title}}</a></p>
+</body>
+</html>
+`))
+
+// sharePageErrorTemplate は共有ページを表示できない場合のページ
+var sharePageErrorTemplate = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.}}</title>
+</head>
+<body>
+<p>{{.}}</p>
+</body>
+</html>
+`))
+
+type EmbedHandler struct {
+	log      *slog.Logger
+	embedSvc *service.EmbedService
+}
+
+func NewEmbedHandler(log *slog.Logger, embedSvc *service.EmbedService) *EmbedHandler {
+	return &EmbedHandler{log: log, embedSvc: embedSvc}
+}
+
+// Page はミュージアムの共有ページ（Open Graph・Twitterカードのメタタグ付き）を返す
+// DiscordやSlackに貼られたリンクのプレビューに使われる。非公開ミュージアムは共有リンクのトークンが必要
+// GET /m/{id}?token={shareToken}
+func (h *EmbedHandler) Page(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		h.renderError(w, http.StatusNotFound, "ミュージアムが見つかりません")
+		return
+	}
+
+	card, err := h.embedSvc.Card(r.Context(), id, r.URL.Query().Get("token"))
+	if err != nil {
+		switch err.Error() {
+		case "museum not found", "share link not found":
+			h.renderError(w, http.StatusNotFound, "ミュージアムが見つかりません")
+		default:
+			logger.FromContext(r.Context(), h.log).Error("failed to build share page",
+				slog.String("error", err.Error()),
+				slog.Int("id", id),
+			)
+			h.renderError(w, http.StatusInternalServerError, "ページを表示できませんでした")
+		}
+		return
+	}
+
+	h.setCacheControl(w, card)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharePageTemplate.Execute(w, card); err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to render share page", slog.String("error", err.Error()))
+	}
+}
+
+// OEmbed はミュージアムのページのURLからoEmbed（rich）のJSONを返す
+// GET /oembed?url={pageURL}&maxwidth=&maxheight=&format=json
+func (h *EmbedHandler) OEmbed(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("url") == "" {
+		HandleError(w, NewBadRequestError("url parameter is required"))
+		return
+	}
+	maxWidth, err := parsePositiveIntQuery(r, "maxwidth")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+	maxHeight, err := parsePositiveIntQuery(r, "maxheight")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	resp, err := h.embedSvc.OEmbed(r.Context(), q.Get("url"), q.Get("format"), maxWidth, maxHeight)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to build oembed",
+			slog.String("error", err.Error()),
+			slog.String("url", q.Get("url")),
+		)
+		HandleError(w, err)
+		return
+	}
+
+	if resp.CacheAge > 0 {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// setCacheControl は公開ミュージアムだけを共有キャッシュに載せる
+func (h *EmbedHandler) setCacheControl(w http.ResponseWriter, card *domain.MuseumCard) {
+	if card.Indexable {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		return
+	}
+	w.Header().Set("Cache-Control", "private, no-cache")
+}
+
+func (h *EmbedHandler) renderError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = sharePageErrorTemplate.Execute(w, message)
+}
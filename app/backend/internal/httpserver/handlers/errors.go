@@ -3,6 +3,8 @@ package handlers
 import (
 	"errors"
 	"net/http"
+
+	"backend/internal/validate"
 )
 
 // HTTPError はHTTPエラーレスポンス用のカスタムエラー型
@@ -17,10 +19,12 @@ func (e HTTPError) Error() string {
 
 // 定義済みエラー
 var (
-	ErrInvalidID          = HTTPError{Code: http.StatusBadRequest, Message: "invalid id"}
-	ErrInvalidRequestBody = HTTPError{Code: http.StatusBadRequest, Message: "invalid request body"}
-	ErrMuseumNotFound     = HTTPError{Code: http.StatusNotFound, Message: "museum not found"}
-	ErrInternalServer     = HTTPError{Code: http.StatusInternalServerError, Message: "internal server error"}
+	ErrInvalidID            = HTTPError{Code: http.StatusBadRequest, Message: "invalid id"}
+	ErrInvalidRequestBody   = HTTPError{Code: http.StatusBadRequest, Message: "invalid request body"}
+	ErrUnsupportedMediaType = HTTPError{Code: http.StatusUnsupportedMediaType, Message: "Content-Type must be application/json"}
+	ErrMuseumNotFound       = HTTPError{Code: http.StatusNotFound, Message: "museum not found"}
+	ErrInternalServer       = HTTPError{Code: http.StatusInternalServerError, Message: "internal server error"}
+	ErrMETUnavailable       = HTTPError{Code: http.StatusServiceUnavailable, Message: "MET API is temporarily unavailable"}
 )
 
 // NewBadRequestError は400エラーを作成する
@@ -46,13 +50,44 @@ func HandleError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	// 検証エラーは失敗したフィールドをすべて返す
+	var validationErrs validate.Errors
+	if errors.As(err, &validationErrs) {
+		respondJSON(w, http.StatusBadRequest, map[string]any{"error": "validation failed", "fields": validationErrs})
+		return
+	}
+
 	// サービス層のエラーメッセージをチェック
 	switch err.Error() {
-	case "museum not found":
-		respondError(w, http.StatusNotFound, "museum not found")
-	case "invalid user ID", "invalid museum ID":
+	case "museum not found", "comment not found", "parent comment not found", "user not found",
+		"share link not found", "member not found", "invitation not found",
+		"revision not found", "artwork not found", "artwork has no image",
+		"url is not a museum page":
+		respondError(w, http.StatusNotFound, err.Error())
+	case "invalid user ID", "invalid museum ID", "invalid comment ID",
+		"search query is required", "search query is too long (max 100)",
+		"comment body is required", "comment body is too long (max 1000)",
+		"invalid object ID", "invalid visibility", "cannot follow yourself",
+		"invalid share scope", "invalid expiresInHours", "invalid share token ID",
+		"invalid role", "invalid email", "invalid invitation ID", "either userId or email is required",
+		"cannot change the creator's role", "cannot remove the creator",
+		"invalid revision ID", "cover file is required":
 		respondError(w, http.StatusBadRequest, err.Error())
+	case "permission denied":
+		respondError(w, http.StatusForbidden, err.Error())
+	case "version mismatch":
+		respondError(w, http.StatusPreconditionFailed, err.Error())
+	case "artwork already in museum", "user is already a member":
+		respondError(w, http.StatusConflict, err.Error())
+	case "comment rejected by moderation":
+		respondError(w, http.StatusUnprocessableEntity, err.Error())
+	case "cover image is too large":
+		respondError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case "cover must be a JPEG, PNG or WebP image":
+		respondError(w, http.StatusUnsupportedMediaType, err.Error())
+	case "only json format is supported":
+		respondError(w, http.StatusNotImplemented, err.Error())
 	default:
 		respondError(w, http.StatusInternalServerError, "internal server error")
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/domain"
+)
+
+// ErrPreconditionRequired はIf-Matchヘッダーのない更新リクエストに返す
+var ErrPreconditionRequired = HTTPError{Code: http.StatusPreconditionRequired, Message: "If-Match header is required"}
+
+// versionETag はミュージアムのバージョンからETagを作る（更新後の応答に返す）
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// museumETag はミュージアム詳細の応答全体に対するETagを作る
+// 本文にはいいね数・閲覧数・フォーク数と呼び出し元ごとのlikedByMeも含まれるため、
+// バージョンに加えてそれらと呼び出し元IDのハッシュを付ける（"{version}-{hash}"）
+// If-Matchにそのまま送り返せるよう、先頭はバージョンにしておく
+func museumETag(m *domain.MuseumResponse, callerID int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%d:%d:%d:%t:%d", m.LikeCount, m.ViewCount, m.ForkCount, m.LikedByMe, callerID))
+	return `"` + strconv.Itoa(m.Version) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// splitETags はIf-Match / If-None-Matchのカンマ区切りの値を分割する
+func splitETags(header string) []string {
+	var tags []string
+	for _, t := range strings.Split(header, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// parseIfMatch はIf-Matchヘッダーから更新の前提となるバージョンを取り出す
+// "*" の場合は0（バージョンを確認しない）を返す。museumETagの "{version}-{hash}" はバージョン部分だけを見る
+// ヘッダーがなければ428、
+// 解釈できない値（弱いETagを含む）の場合は現在のバージョンと一致し得ないため version mismatch を返す
+func parseIfMatch(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, ErrPreconditionRequired
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	tags := splitETags(header)
+	if len(tags) != 1 || !strings.HasPrefix(tags[0], `"`) || !strings.HasSuffix(tags[0], `"`) || len(tags[0]) < 2 {
+		return 0, errors.New("version mismatch")
+	}
+	value, _, _ := strings.Cut(strings.Trim(tags[0], `"`), "-")
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, errors.New("version mismatch")
+	}
+	return version, nil
+}
+
+// notModified はIf-None-MatchがETagと一致するか（弱い比較）を判定する
+func notModified(r *http.Request, etag string) bool {
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, t := range splitETags(header) {
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
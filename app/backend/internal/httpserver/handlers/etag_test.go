@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"backend/internal/domain"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header      string
+		wantVersion int
+		wantErr     string
+	}{
+		{`"3"`, 3, ""},
+		{` "3" `, 3, ""},
+		{`*`, 0, ""},
+		// 詳細取得のETag（"{version}-{hash}"）はバージョン部分だけを見る
+		{`"3-0123456789abcdef"`, 3, ""},
+		// 更新の前提には強い比較が必要なため、弱いETagは一致しないものとして扱う
+		{`W/"3"`, 0, "version mismatch"},
+		{`"3", "4"`, 0, "version mismatch"},
+		{`3`, 0, "version mismatch"},
+		{`"abc"`, 0, "version mismatch"},
+		{`"0"`, 0, "version mismatch"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPatch, "/", nil)
+		r.Header.Set("If-Match", tt.header)
+		version, err := parseIfMatch(r)
+		if tt.wantErr == "" {
+			if err != nil || version != tt.wantVersion {
+				t.Errorf("If-Match %s: got %d, %v; want %d", tt.header, version, err, tt.wantVersion)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("If-Match %s: got %d, %v; want error %q", tt.header, version, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseIfMatchRequired(t *testing.T) {
+	_, err := parseIfMatch(httptest.NewRequest(http.MethodPatch, "/", nil))
+	if !errors.Is(err, ErrPreconditionRequired) {
+		t.Fatalf("expected ErrPreconditionRequired, got %v", err)
+	}
+	w := httptest.NewRecorder()
+	HandleError(w, err)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("status = %d, want 428", w.Code)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	const etag = `"3-0123456789abcdef"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{etag, true},
+		// If-None-Matchは弱い比較なので、W/付きでも一致する
+		{`W/` + etag, true},
+		{`"2-0123456789abcdef", ` + etag, true},
+		{`*`, true},
+		{`"3"`, false},
+		{`"3-fedcba9876543210"`, false},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			r.Header.Set("If-None-Match", tt.header)
+		}
+		if got := notModified(r, etag); got != tt.want {
+			t.Errorf("If-None-Match %q: got %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestMuseumETagChangesWithStatsAndCaller(t *testing.T) {
+	m := &domain.MuseumResponse{Version: 3, LikeCount: 1}
+	base := museumETag(m, 5)
+
+	liked := *m
+	liked.LikeCount, liked.LikedByMe = 2, true
+	viewed := *m
+	viewed.ViewCount = 1
+	for name, got := range map[string]string{
+		"like":         museumETag(&liked, 5),
+		"view":         museumETag(&viewed, 5),
+		"other caller": museumETag(m, 6),
+	} {
+		if got == base {
+			t.Errorf("expected the ETag to change after %s", name)
+		}
+	}
+	if museumETag(m, 5) != base {
+		t.Error("expected the same ETag for the same response")
+	}
+
+	// 詳細のETagはそのままIf-Matchに使える
+	r := httptest.NewRequest(http.MethodPatch, "/", nil)
+	r.Header.Set("If-Match", base)
+	if version, err := parseIfMatch(r); err != nil || version != 3 {
+		t.Errorf("parseIfMatch(%s) = %d, %v; want 3", base, version, err)
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"backend/internal/logger"
+	"backend/internal/metclient"
+	"backend/internal/service"
+)
+
+// exportWriteTimeout は書き出しの応答に許す時間（サーバー全体のWriteTimeoutでは作品画像の取得が間に合わない）
+const exportWriteTimeout = 5 * time.Minute
+
+type ExportHandler struct {
+	log       *slog.Logger
+	exportSvc *service.ExportService
+	shareSvc  *service.ShareService
+}
+
+func NewExportHandler(log *slog.Logger, exportSvc *service.ExportService, shareSvc *service.ShareService) *ExportHandler {
+	return &ExportHandler{log: log, exportSvc: exportSvc, shareSvc: shareSvc}
+}
+
+// Export はミュージアムを静的なHTMLギャラリーのZIPとして返す
+// 共有リンクのトークンを指定すると非公開ミュージアムも書き出せる
+// GET /api/v1/museums/{id}/export.zip?token={shareToken}
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	shared, err := resolveShareToken(r, h.shareSvc, museumID)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	// 作品画像の取得とZIPの送信に時間がかかるため、このリクエストだけ書き込みの期限を延ばす
+	extendWriteDeadline(w, r, h.log, exportWriteTimeout)
+
+	var export *service.MuseumExport
+	if shared {
+		export, err = h.exportSvc.PrepareShared(r.Context(), museumID)
+	} else {
+		export, err = h.exportSvc.Prepare(r.Context(), museumID, callerID)
+	}
+	if err != nil {
+		// 見つからない・権限がないのは利用者側の問題なので、想定外のエラーだけをログに残す
+		switch {
+		case errors.Is(err, metclient.ErrCircuitOpen):
+			logger.FromContext(r.Context(), h.log).Warn("museum export skipped while MET is unavailable",
+				slog.Int("museumId", museumID),
+			)
+			HandleError(w, ErrMETUnavailable)
+		case err.Error() == "museum not found", err.Error() == "permission denied":
+			HandleError(w, err)
+		default:
+			logger.FromContext(r.Context(), h.log).Error("failed to prepare museum export",
+				slog.String("error", err.Error()),
+				slog.Int("museumId", museumID),
+			)
+			HandleError(w, err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName()+`"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	// ヘッダーを送った後なので、失敗してもログに残すことしかできない
+	if err := export.WriteZip(w); err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to write museum export",
+			slog.String("error", err.Error()),
+			slog.Int("museumId", museumID),
+		)
+	}
+}
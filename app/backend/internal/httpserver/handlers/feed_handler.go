@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type FeedHandler struct {
+	log         *slog.Logger
+	activitySvc *service.ActivityService
+}
+
+func NewFeedHandler(log *slog.Logger, activitySvc *service.ActivityService) *FeedHandler {
+	return &FeedHandler{log: log, activitySvc: activitySvc}
+}
+
+// GetFeed はフォローしているユーザーの最近のアクティビティを取得する
+// GET /api/v1/feed?limit=20&before={nextCursor}
+func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	before, err := parsePositiveIntQuery(r, "before")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+	limit := parseOptionalIntQuery(r, "limit", 20)
+
+	feed, err := h.activitySvc.GetFeed(r.Context(), callerID, before, limit)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to get feed",
+			slog.String("error", err.Error()),
+			slog.Int("callerId", callerID),
+		)
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, feed)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/logger"
+	"backend/internal/metclient"
+	"backend/internal/service"
+)
+
+// imageCacheControl は変換済み画像のCache-Control
+// 同じURLの内容は変わらない（作品画像の差し替えはキャッシュキーのバージョンで対応する）ため長期間キャッシュさせる
+const imageCacheControl = "public, max-age=31536000, immutable"
+
+// imageWriteTimeout は画像の応答に許す時間
+// 作品情報の取得と元画像のダウンロードはそれぞれ再試行するため、サーバー全体のWriteTimeoutでは間に合わないことがある
+const imageWriteTimeout = 3 * time.Minute
+
+type ImageHandler struct {
+	log      *slog.Logger
+	imageSvc *service.ImageService
+}
+
+func NewImageHandler(log *slog.Logger, imageSvc *service.ImageService) *ImageHandler {
+	return &ImageHandler{log: log, imageSvc: imageSvc}
+}
+
+// Get は作品画像を縮小・再エンコードして返す
+// canvasで扱えるよう、どのオリジンからでも読み込めるようにする
+// GET /api/v1/images/{objectId}?w=400&h=300&fit=cover&format=webp
+func (h *ImageHandler) Get(w http.ResponseWriter, r *http.Request) {
+	objectID, err := parsePositiveIntParam(r, "objectId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+	opts, err := parseImageOptions(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	extendWriteDeadline(w, r, h.log, imageWriteTimeout)
+
+	img, err := h.imageSvc.GetImage(r.Context(), objectID, opts)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to get image",
+			slog.String("error", err.Error()),
+			slog.Int("objectId", objectID),
+		)
+		switch {
+		case errors.Is(err, service.ErrMetObjectNotFound):
+			HandleError(w, NewNotFoundError("MET object not found"))
+		case errors.Is(err, metclient.ErrCircuitOpen):
+			HandleError(w, ErrMETUnavailable)
+		case errors.Is(err, service.ErrArtworkHasNoImage):
+			HandleError(w, err)
+		default:
+			respondError(w, http.StatusBadGateway, "failed to fetch MET image")
+		}
+		return
+	}
+
+	w.Header().Set("Cache-Control", imageCacheControl)
+	w.Header().Set("ETag", img.ETag)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+	if r.Header.Get("If-None-Match") == img.ETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", img.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(img.Data)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(img.Data)
+}
+
+// parseImageOptions はクエリから画像の変換指定を解析する（値の範囲はサービス層で検証する）
+func parseImageOptions(r *http.Request) (service.ImageOptions, error) {
+	q := r.URL.Query()
+	opts := service.ImageOptions{
+		Fit:    service.ImageFit(q.Get("fit")),
+		Format: service.ImageFormat(q.Get("format")),
+	}
+	for name, dst := range map[string]*int{"w": &opts.Width, "h": &opts.Height} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return opts, NewBadRequestError(fmt.Sprintf("invalid %s", name))
+		}
+		*dst = v
+	}
+	return opts, nil
+}
+
+// extendWriteDeadline は時間のかかるリクエストだけ書き込みの期限を延ばす
+// 延ばせなかった場合もサーバー全体のWriteTimeoutのまま処理を続ける
+func extendWriteDeadline(w http.ResponseWriter, r *http.Request, log *slog.Logger, d time.Duration) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d)); err != nil {
+		logger.FromContext(r.Context(), log).Warn("failed to extend write deadline", slog.String("error", err.Error()))
+	}
+}
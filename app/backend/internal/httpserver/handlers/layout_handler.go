@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+// renderWriteTimeout は合成画像の応答に許す時間
+const renderWriteTimeout = 3 * time.Minute
+
+type LayoutHandler struct {
+	log       *slog.Logger
+	layoutSvc *service.LayoutService
+	shareSvc  *service.ShareService
+}
+
+func NewLayoutHandler(log *slog.Logger, layoutSvc *service.LayoutService, shareSvc *service.ShareService) *LayoutHandler {
+	return &LayoutHandler{log: log, layoutSvc: layoutSvc, shareSvc: shareSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *LayoutHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// Get はミュージアムの部屋の背景と作品の配置を取得する
+// 共有リンクのトークンを指定すると非公開ミュージアムの配置も取得できる
+// GET /api/v1/museums/{id}/layout?token={shareToken}
+func (h *LayoutHandler) Get(w http.ResponseWriter, r *http.Request) {
+	museumID, callerID, shared, ok := h.parseViewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var layout *domain.MuseumLayout
+	var err error
+	if shared {
+		layout, err = h.layoutSvc.GetSharedLayout(r.Context(), museumID)
+	} else {
+		layout, err = h.layoutSvc.GetLayout(r.Context(), museumID, callerID)
+	}
+	if err != nil {
+		h.logError(r, "failed to get layout", err, slog.Int("museumId", museumID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, layout)
+}
+
+// Update はミュージアムの配置を置き換える（所有者・編集者のみ）
+// PUT /api/v1/museums/{id}/layout
+func (h *LayoutHandler) Update(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.MuseumLayoutUpdateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	layout, err := h.layoutSvc.UpdateLayout(r.Context(), museumID, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to update layout", err, slog.Int("museumId", museumID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, layout)
+}
+
+// Render はミュージアムの部屋の合成画像（PNG）を返す
+// 配置が変わるとETagも変わるため、If-None-Matchで再取得を省ける
+// GET /api/v1/museums/{id}/render.png?token={shareToken}
+func (h *LayoutHandler) Render(w http.ResponseWriter, r *http.Request) {
+	museumID, callerID, shared, ok := h.parseViewRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// 作品画像をすべて取得して合成するため、サーバー全体のWriteTimeoutでは間に合わないことがある
+	extendWriteDeadline(w, r, h.log, renderWriteTimeout)
+
+	var img *service.Image
+	var err error
+	if shared {
+		img, err = h.layoutSvc.RenderShared(r.Context(), museumID)
+	} else {
+		img, err = h.layoutSvc.Render(r.Context(), museumID, callerID)
+	}
+	if err != nil {
+		h.logError(r, "failed to render museum", err, slog.Int("museumId", museumID))
+		HandleError(w, err)
+		return
+	}
+
+	// 非公開のミュージアムもあるため共有キャッシュには置かせず、毎回ETagで確認させる
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("ETag", img.ETag)
+	if r.Header.Get("If-None-Match") == img.ETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", img.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(img.Data)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(img.Data)
+}
+
+// parseViewRequest は閲覧系のリクエストからミュージアムID・呼び出しユーザー・共有リンク経由かを取り出す
+// 失敗した場合はエラーレスポンスを書いてokをfalseで返す
+func (h *LayoutHandler) parseViewRequest(w http.ResponseWriter, r *http.Request) (museumID, callerID int, shared, ok bool) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return 0, 0, false, false
+	}
+
+	callerID, err = parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return 0, 0, false, false
+	}
+
+	shared, err = resolveShareToken(r, h.shareSvc, museumID)
+	if err != nil {
+		HandleError(w, err)
+		return 0, 0, false, false
+	}
+	return museumID, callerID, shared, true
+}
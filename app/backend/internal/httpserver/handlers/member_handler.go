@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type MemberHandler struct {
+	log       *slog.Logger
+	memberSvc *service.MemberService
+}
+
+func NewMemberHandler(log *slog.Logger, memberSvc *service.MemberService) *MemberHandler {
+	return &MemberHandler{log: log, memberSvc: memberSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *MemberHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// List はミュージアムのメンバー一覧を取得する（メンバーのみ）
+// GET /api/v1/museums/{id}/members
+func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	members, err := h.memberSvc.ListMembers(r.Context(), museumID, callerID)
+	if err != nil {
+		h.logError(r, "failed to list members", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, members)
+}
+
+// UpdateRole はメンバーの役割を変更する（owner役割のみ）
+// PATCH /api/v1/museums/{id}/members/{userId}
+func (h *MemberHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	targetID, err := parsePositiveIntParam(r, "userId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.MuseumMemberUpdateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.memberSvc.UpdateRole(r.Context(), museumID, targetID, callerID, req.Role); err != nil {
+		h.logError(r, "failed to update member role", err, slog.Int("museumId", museumID), slog.Int("userId", targetID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Remove はメンバーを外す（owner役割のみ。本人は自分で抜けられる）
+// DELETE /api/v1/museums/{id}/members/{userId}
+func (h *MemberHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	targetID, err := parsePositiveIntParam(r, "userId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.memberSvc.RemoveMember(r.Context(), museumID, targetID, callerID); err != nil {
+		h.logError(r, "failed to remove member", err, slog.Int("museumId", museumID), slog.Int("userId", targetID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Invite は共同キュレーターを招待する（owner役割のみ）
+// POST /api/v1/museums/{id}/invitations
+func (h *MemberHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.MuseumInvitationCreateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	inv, err := h.memberSvc.Invite(r.Context(), museumID, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to create invitation", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, inv)
+}
+
+// ListMuseumInvitations はミュージアムの保留中の招待を取得する（owner役割のみ）
+// GET /api/v1/museums/{id}/invitations
+func (h *MemberHandler) ListMuseumInvitations(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	invitations, err := h.memberSvc.ListMuseumInvitations(r.Context(), museumID, callerID)
+	if err != nil {
+		h.logError(r, "failed to list invitations", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invitations)
+}
+
+// RevokeInvitation は保留中の招待を取り消す（owner役割のみ）
+// DELETE /api/v1/museums/{id}/invitations/{invitationId}
+func (h *MemberHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	invitationID, err := parsePositiveIntParam(r, "invitationId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.memberSvc.RevokeInvitation(r.Context(), museumID, invitationID, callerID); err != nil {
+		h.logError(r, "failed to revoke invitation", err, slog.Int("museumId", museumID), slog.Int("invitationId", invitationID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMine は自分宛ての保留中の招待を取得する
+// GET /api/v1/invitations
+func (h *MemberHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	invitations, err := h.memberSvc.ListMyInvitations(r.Context(), callerID)
+	if err != nil {
+		h.logError(r, "failed to list my invitations", err, slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, invitations)
+}
+
+// Accept は自分宛ての招待を承諾する
+// POST /api/v1/invitations/{invitationId}/accept
+func (h *MemberHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	invitationID, err := parsePositiveIntParam(r, "invitationId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.memberSvc.AcceptInvitation(r.Context(), invitationID, callerID); err != nil {
+		h.logError(r, "failed to accept invitation", err, slog.Int("invitationId", invitationID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Decline は自分宛ての招待を辞退する
+// POST /api/v1/invitations/{invitationId}/decline
+func (h *MemberHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	invitationID, err := parsePositiveIntParam(r, "invitationId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.memberSvc.DeclineInvitation(r.Context(), invitationID, callerID); err != nil {
+		h.logError(r, "failed to decline invitation", err, slog.Int("invitationId", invitationID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"backend/internal/logger"
+	"backend/internal/metclient"
 	"backend/internal/service"
 )
 
@@ -25,12 +30,21 @@ func (h *MetHandler) GetObjectByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	obj, err := h.metSvc.GetObjectByID(id)
+	obj, err := h.metSvc.GetObjectByID(r.Context(), id)
 	if err != nil {
-		h.log.Error("MET API error",
+		logger.FromContext(r.Context(), h.log).Error("MET API error",
 			slog.String("error", err.Error()),
 			slog.Int("id", id),
 		)
+		if errors.Is(err, service.ErrMetObjectNotFound) {
+			HandleError(w, NewNotFoundError("MET object not found"))
+			return
+		}
+		// 障害が続いてサーキットブレーカーが開いている間は503を返す
+		if errors.Is(err, metclient.ErrCircuitOpen) {
+			HandleError(w, ErrMETUnavailable)
+			return
+		}
 		respondError(w, http.StatusBadGateway, fmt.Sprintf("MET API error: %v", err))
 		return
 	}
@@ -38,3 +52,54 @@ func (h *MetHandler) GetObjectByID(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, obj)
 }
 
+// GetObjectsByIDs は複数の作品情報をまとめて取得する
+// 取得できなかった作品はerrorsに入れ、それ以外は指定した順にobjectsで返す
+// GET /api/v1/met/objects?ids=1,2,3
+func (h *MetHandler) GetObjectsByIDs(w http.ResponseWriter, r *http.Request) {
+	ids, err := parseIDList(r.URL.Query().Get("ids"), service.MetObjectsBatchMax)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	batch, err := h.metSvc.GetObjectsByIDs(r.Context(), ids)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("MET API error",
+			slog.String("error", err.Error()),
+			slog.Int("count", len(ids)),
+		)
+		if errors.Is(err, metclient.ErrCircuitOpen) {
+			HandleError(w, ErrMETUnavailable)
+			return
+		}
+		respondError(w, http.StatusBadGateway, fmt.Sprintf("MET API error: %v", err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, batch)
+}
+
+// parseIDList はカンマ区切りの正の整数IDを解析する（重複は最初の1つだけ残す）
+func parseIDList(raw string, max int) ([]int, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, NewBadRequestError("ids is required")
+	}
+	parts := strings.Split(raw, ",")
+	seen := make(map[int]bool, len(parts))
+	ids := make([]int, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, NewBadRequestError(fmt.Sprintf("invalid id %q in ids", part))
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	if len(ids) > max {
+		return nil, NewBadRequestError(fmt.Sprintf("ids must not contain more than %d ids", max))
+	}
+	return ids, nil
+}
@@ -1,29 +1,38 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
 
 	"backend/internal/domain"
+	"backend/internal/logger"
 	"backend/internal/service"
 )
 
 type MuseumHandler struct {
-	log       *slog.Logger
-	museumSvc *service.MuseumService
+	log            *slog.Logger
+	museumSvc      *service.MuseumService
+	shareSvc       *service.ShareService
+	trustedProxies []netip.Prefix // X-Forwarded-Forを信頼するリバースプロキシ
 }
 
-func NewMuseumHandler(log *slog.Logger, museumSvc *service.MuseumService) *MuseumHandler {
-	return &MuseumHandler{log: log, museumSvc: museumSvc}
+func NewMuseumHandler(log *slog.Logger, museumSvc *service.MuseumService, shareSvc *service.ShareService, trustedProxies []netip.Prefix) *MuseumHandler {
+	return &MuseumHandler{log: log, museumSvc: museumSvc, shareSvc: shareSvc, trustedProxies: trustedProxies}
 }
 
-// logError はエラーログを出力するヘルパーメソッド
-func (h *MuseumHandler) logError(message string, err error, attrs ...slog.Attr) {
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *MuseumHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
 	args := []any{slog.String("error", err.Error())}
 	for _, attr := range attrs {
 		args = append(args, attr)
 	}
-	h.log.Error(message, args...)
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
 }
 
 // GetPublicMuseumsExceptUser は指定ユーザー以外の公開ミュージアムを取得する
@@ -37,9 +46,9 @@ func (h *MuseumHandler) GetPublicMuseumsExceptUser(w http.ResponseWriter, r *htt
 
 	limit := parseOptionalIntQuery(r, "limit", 10)
 
-	museums, err := h.museumSvc.GetOtherUsersPublicMuseums(excludeUserID, limit)
+	museums, err := h.museumSvc.GetOtherUsersPublicMuseums(r.Context(), excludeUserID, limit)
 	if err != nil {
-		h.logError("failed to get public museums", err, slog.Int("excludeUserId", excludeUserID), slog.Int("limit", limit))
+		h.logError(r, "failed to get public museums", err, slog.Int("excludeUserId", excludeUserID), slog.Int("limit", limit))
 		HandleError(w, NewInternalServerError("failed to get museums"))
 		return
 	}
@@ -47,8 +56,33 @@ func (h *MuseumHandler) GetPublicMuseumsExceptUser(w http.ResponseWriter, r *htt
 	respondJSON(w, http.StatusOK, museums)
 }
 
+// Search は名前・説明文でミュージアムを検索する
+// GET /api/v1/museums/search?q={query}&limit=20&offset=0
+func (h *MuseumHandler) Search(w http.ResponseWriter, r *http.Request) {
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	q := r.URL.Query().Get("q")
+	limit := parseOptionalIntQuery(r, "limit", 20)
+	offset := parseOptionalIntQuery(r, "offset", 0)
+
+	result, err := h.museumSvc.SearchMuseums(r.Context(), q, callerID, limit, offset)
+	if err != nil {
+		h.logError(r, "failed to search museums", err, slog.String("q", q), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
 // GetMuseumByID は指定IDのミュージアム詳細を取得する
-// GET /api/v1/museums/{id}
+// 共有リンクのトークンを指定すると非公開ミュージアムも取得できる
+// ETagを返し、If-None-Matchが一致すれば304を返す
+// GET /api/v1/museums/{id}?token={shareToken}
 func (h *MuseumHandler) GetMuseumByID(w http.ResponseWriter, r *http.Request) {
 	id, err := parsePositiveIntParam(r, "id")
 	if err != nil {
@@ -56,17 +90,49 @@ func (h *MuseumHandler) GetMuseumByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	museum, err := h.museumSvc.GetMuseumByID(id)
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	shared, err := resolveShareToken(r, h.shareSvc, id)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var museum *domain.MuseumResponse
+	if shared {
+		museum, err = h.museumSvc.GetSharedMuseum(r.Context(), id, callerID)
+	} else {
+		museum, err = h.museumSvc.GetMuseumByID(r.Context(), id, callerID)
+	}
 	if err != nil {
-		h.logError("failed to get museum", err, slog.Int("id", id))
+		h.logError(r, "failed to get museum", err, slog.Int("id", id))
 		HandleError(w, err)
 		return
 	}
 
+	// 閲覧できた場合だけ記録する。記録に失敗しても詳細は返す
+	if err := h.museumSvc.RecordView(r.Context(), id, visitorKey(r, callerID, h.trustedProxies)); err != nil {
+		h.logError(r, "failed to record museum view", err, slog.Int("id", id))
+	}
+
+	etag := museumETag(museum, callerID)
+	w.Header().Set("ETag", etag)
+	// 非公開ミュージアムを共有キャッシュに載せないよう、ブラウザ内でのみ再検証付きでキャッシュさせる
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if notModified(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	respondJSON(w, http.StatusOK, museum)
 }
 
-// UpdateTitle はミュージアムのタイトルを更新する
+// UpdateTitle はミュージアムのタイトルを更新する（所有者・編集者のみ）
+// If-Matchに取得時のETagが必要で、他で更新されていれば412を返す
 // PATCH /api/v1/museums/{id}/title
 func (h *MuseumHandler) UpdateTitle(w http.ResponseWriter, r *http.Request) {
 	id, err := parsePositiveIntParam(r, "id")
@@ -75,25 +141,72 @@ func (h *MuseumHandler) UpdateTitle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
 	var req domain.MuseumTitleUpdateRequest
 	if err := decodeJSONBody(r, &req); err != nil {
 		HandleError(w, err)
 		return
 	}
 
-	if err := validateMuseumTitleUpdateRequest(req); err != nil {
+	newVersion, err := h.museumSvc.UpdateTitle(r.Context(), id, callerID, req.Title, version)
+	if err != nil {
+		h.logError(r, "failed to update museum title", err, slog.Int("id", id), slog.String("title", req.Title))
+		HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(newVersion))
+	respondJSON(w, http.StatusOK, map[string]any{"message": "title updated successfully", "version": newVersion})
+}
+
+// UpdateVisibility はミュージアムの公開設定を変更する（owner役割のみ）
+// If-Matchに取得時のETagが必要で、他で更新されていれば412を返す
+// PATCH /api/v1/museums/{id}/visibility
+func (h *MuseumHandler) UpdateVisibility(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
 		HandleError(w, err)
 		return
 	}
 
-	err = h.museumSvc.UpdateTitle(id, req.Title)
+	callerID, err := requireCallerID(r)
 	if err != nil {
-		h.logError("failed to update museum title", err, slog.Int("id", id), slog.String("title", req.Title))
 		HandleError(w, err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "title updated successfully"})
+	version, err := parseIfMatch(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.MuseumVisibilityUpdateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	museum, err := h.museumSvc.UpdateVisibility(r.Context(), id, callerID, req.Visibility, version)
+	if err != nil {
+		h.logError(r, "failed to update museum visibility", err, slog.Int("id", id), slog.String("visibility", string(req.Visibility)))
+		HandleError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", versionETag(museum.Version))
+	respondJSON(w, http.StatusOK, museum)
 }
 
 // Create は新しいミュージアムを作成する
@@ -105,17 +218,164 @@ func (h *MuseumHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := validateMuseumCreateRequest(req); err != nil {
+	museum, err := h.museumSvc.Create(r.Context(), req)
+	if err != nil {
+		h.logError(r, "failed to create museum", err, slog.Int("userId", req.UserID), slog.String("name", req.Name))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, museum)
+}
+
+// Delete はミュージアムをゴミ箱に移す（owner役割のみ）
+// DELETE /api/v1/museums/{id}
+func (h *MuseumHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
 		HandleError(w, err)
 		return
 	}
 
-	museum, err := h.museumSvc.Create(req)
+	callerID, err := requireCallerID(r)
 	if err != nil {
-		h.logError("failed to create museum", err, slog.Int("userId", req.UserID), slog.String("name", req.Name))
-		HandleError(w, NewInternalServerError("failed to create museum"))
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.museumSvc.DeleteMuseum(r.Context(), id, callerID); err != nil {
+		h.logError(r, "failed to delete museum", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Fork はミュージアムを複製し、呼び出しユーザーの非公開ミュージアムとして作成する
+// POST /api/v1/museums/{id}/fork
+func (h *MuseumHandler) Fork(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	museum, err := h.museumSvc.ForkMuseum(r.Context(), id, callerID)
+	if err != nil {
+		h.logError(r, "failed to fork museum", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, museum)
-}
\ No newline at end of file
+}
+
+// Like はミュージアムにいいねを付ける（冪等）
+// POST /api/v1/museums/{id}/like
+func (h *MuseumHandler) Like(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	result, err := h.museumSvc.LikeMuseum(r.Context(), id, callerID)
+	if err != nil {
+		h.logError(r, "failed to like museum", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Unlike はミュージアムのいいねを取り消す（冪等）
+// DELETE /api/v1/museums/{id}/like
+func (h *MuseumHandler) Unlike(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	result, err := h.museumSvc.UnlikeMuseum(r.Context(), id, callerID)
+	if err != nil {
+		h.logError(r, "failed to unlike museum", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// visitorKey は閲覧数の重複排除に使う訪問者キーを返す
+// ログインユーザーはユーザーID、匿名はIPとUser-Agentのハッシュ（生のIPは保存しない）
+func visitorKey(r *http.Request, callerID int, trustedProxies []netip.Prefix) string {
+	if callerID > 0 {
+		return "user:" + strconv.Itoa(callerID)
+	}
+
+	ip := clientIP(r, trustedProxies)
+	sum := sha256.Sum256([]byte(ip + "|" + r.UserAgent()))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+// clientIP はリクエスト元のIPを返す
+// X-Forwarded-Forは誰でも書けるため、接続元（RemoteAddr）が信頼するプロキシの場合だけ参照し、
+// 右から順に信頼するプロキシを読み飛ばして最初に現れたアドレスをクライアントとする
+func clientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !isTrustedProxy(hop, trustedProxies) {
+			return hop
+		}
+		ip = hop
+	}
+	// すべて信頼するプロキシだった場合は最も外側のものを使う
+	return ip
+}
+
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
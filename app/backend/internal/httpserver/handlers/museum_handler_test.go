@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.0.2.1/32")}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		trusted    []netip.Prefix
+		want       string
+	}{
+		{"no proxy", "203.0.113.5:1234", nil, trusted, "203.0.113.5"},
+		// 信頼するプロキシの設定がなければX-Forwarded-Forは見ない
+		{"no trusted proxies", "10.0.0.1:1234", []string{"198.51.100.7"}, nil, "10.0.0.1"},
+		// 信頼しない接続元が送ったX-Forwarded-Forは偽装できるため無視する
+		{"untrusted peer", "203.0.113.5:1234", []string{"198.51.100.7"}, trusted, "203.0.113.5"},
+		{"trusted peer", "10.0.0.1:1234", []string{"198.51.100.7"}, trusted, "198.51.100.7"},
+		// クライアントが先頭に偽のアドレスを入れても、右から見て最初の信頼しないアドレスを使う
+		{"spoofed first hop", "10.0.0.1:1234", []string{"1.2.3.4, 198.51.100.7, 192.0.2.1"}, trusted, "198.51.100.7"},
+		{"multiple headers", "10.0.0.1:1234", []string{"1.2.3.4", "198.51.100.7, 10.0.0.2"}, trusted, "198.51.100.7"},
+		{"only proxies", "10.0.0.1:1234", []string{"10.0.0.3, 10.0.0.2"}, trusted, "10.0.0.3"},
+		{"trusted peer without header", "10.0.0.1:1234", nil, trusted, "10.0.0.1"},
+		{"ipv6 peer", "[2001:db8::1]:1234", []string{"198.51.100.7"}, trusted, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		for _, v := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := clientIP(r, tt.trusted); got != tt.want {
+			t.Errorf("%s: clientIP = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestVisitorKey(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	newRequest := func(remoteAddr, forwarded, userAgent string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remoteAddr
+		if forwarded != "" {
+			r.Header.Set("X-Forwarded-For", forwarded)
+		}
+		r.Header.Set("User-Agent", userAgent)
+		return r
+	}
+
+	if got := visitorKey(newRequest("203.0.113.5:1", "", "ua"), 42, trusted); got != "user:42" {
+		t.Errorf("logged-in visitor: got %q, want user:42", got)
+	}
+
+	anon := visitorKey(newRequest("203.0.113.5:1", "", "ua"), 0, trusted)
+	if len(anon) != len("anon:")+32 || anon[:5] != "anon:" {
+		t.Fatalf("unexpected anonymous key %q", anon)
+	}
+	if got := visitorKey(newRequest("203.0.113.5:2", "", "ua"), 0, trusted); got != anon {
+		t.Errorf("expected the same key regardless of the source port, got %q and %q", anon, got)
+	}
+	if got := visitorKey(newRequest("203.0.113.5:1", "", "other"), 0, trusted); got == anon {
+		t.Error("expected a different key for a different User-Agent")
+	}
+	// 信頼しない接続元はX-Forwarded-Forを変えても別の訪問者にはなれない
+	if got := visitorKey(newRequest("203.0.113.5:1", "198.51.100.7", "ua"), 0, trusted); got != anon {
+		t.Error("expected X-Forwarded-For from an untrusted peer to be ignored")
+	}
+	// 信頼するプロキシ経由ならX-Forwarded-Forのクライアントで数える
+	if got := visitorKey(newRequest("10.0.0.1:1", "203.0.113.5", "ua"), 0, trusted); got != anon {
+		t.Error("expected the forwarded client behind a trusted proxy to be used")
+	}
+}
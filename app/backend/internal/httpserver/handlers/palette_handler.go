@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logger"
+	"backend/internal/metclient"
+	"backend/internal/service"
+)
+
+type PaletteHandler struct {
+	log        *slog.Logger
+	paletteSvc *service.PaletteService
+}
+
+func NewPaletteHandler(log *slog.Logger, paletteSvc *service.PaletteService) *PaletteHandler {
+	return &PaletteHandler{log: log, paletteSvc: paletteSvc}
+}
+
+// Get は作品画像の代表色と平均の明るさを返す（未計算ならその場で計算して保存する）
+// GET /api/v1/met/objects/{id}/palette
+func (h *PaletteHandler) Get(w http.ResponseWriter, r *http.Request) {
+	objectID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	palette, err := h.paletteSvc.Get(r.Context(), objectID)
+	if err != nil {
+		logger.FromContext(r.Context(), h.log).Error("failed to get palette",
+			slog.String("error", err.Error()),
+			slog.Int("objectId", objectID),
+		)
+		switch {
+		case errors.Is(err, service.ErrMetObjectNotFound):
+			HandleError(w, NewNotFoundError("MET object not found"))
+		case errors.Is(err, metclient.ErrCircuitOpen):
+			HandleError(w, ErrMETUnavailable)
+		default:
+			respondError(w, http.StatusBadGateway, "failed to compute palette")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, palette)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type RevisionHandler struct {
+	log         *slog.Logger
+	revisionSvc *service.RevisionService
+}
+
+func NewRevisionHandler(log *slog.Logger, revisionSvc *service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{log: log, revisionSvc: revisionSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *RevisionHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// List はミュージアムのリビジョンを新しい順に取得する（所有者・編集者のみ）
+// GET /api/v1/museums/{id}/revisions?limit=20&offset=0
+func (h *RevisionHandler) List(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	limit := parseOptionalIntQuery(r, "limit", 20)
+	offset := parseOptionalIntQuery(r, "offset", 0)
+
+	page, err := h.revisionSvc.ListRevisions(r.Context(), museumID, callerID, limit, offset)
+	if err != nil {
+		h.logError(r, "failed to list revisions", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// Get はスナップショットを含むリビジョンを取得する（所有者・編集者のみ）
+// GET /api/v1/museums/{id}/revisions/{revisionId}
+func (h *RevisionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	revisionID, err := parsePositiveIntParam(r, "revisionId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	rev, err := h.revisionSvc.GetRevision(r.Context(), museumID, revisionID, callerID)
+	if err != nil {
+		h.logError(r, "failed to get revision", err, slog.Int("museumId", museumID), slog.Int("revisionId", revisionID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rev)
+}
+
+// Diff は2つのリビジョンの差分を取得する（所有者・編集者のみ）
+// GET /api/v1/museums/{id}/revisions/diff?from=1&to=2
+func (h *RevisionHandler) Diff(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	fromID, err := parseRequiredIntQuery(r, "from")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	toID, err := parseRequiredIntQuery(r, "to")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	diff, err := h.revisionSvc.Diff(r.Context(), museumID, fromID, toID, callerID)
+	if err != nil {
+		h.logError(r, "failed to diff revisions", err, slog.Int("museumId", museumID), slog.Int("from", fromID), slog.Int("to", toID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, diff)
+}
+
+// Restore は過去のリビジョンの内容にミュージアムを戻す（所有者・編集者のみ）
+// POST /api/v1/museums/{id}/revisions/{revisionId}/restore
+func (h *RevisionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	revisionID, err := parsePositiveIntParam(r, "revisionId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	rev, err := h.revisionSvc.Restore(r.Context(), museumID, revisionID, callerID)
+	if err != nil {
+		h.logError(r, "failed to restore revision", err, slog.Int("museumId", museumID), slog.Int("revisionId", revisionID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rev)
+}
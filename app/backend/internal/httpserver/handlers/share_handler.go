@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type ShareHandler struct {
+	log        *slog.Logger
+	shareSvc   *service.ShareService
+	museumSvc  *service.MuseumService
+	artworkSvc *service.ArtworkService
+}
+
+func NewShareHandler(log *slog.Logger, shareSvc *service.ShareService, museumSvc *service.MuseumService, artworkSvc *service.ArtworkService) *ShareHandler {
+	return &ShareHandler{log: log, shareSvc: shareSvc, museumSvc: museumSvc, artworkSvc: artworkSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *ShareHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// Create は共有リンクを発行する（所有者のみ）
+// POST /api/v1/museums/{id}/shares
+func (h *ShareHandler) Create(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	var req domain.ShareTokenCreateRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	token, err := h.shareSvc.CreateToken(r.Context(), museumID, callerID, req)
+	if err != nil {
+		h.logError(r, "failed to create share token", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, token)
+}
+
+// List は共有リンクの一覧を取得する（所有者のみ）
+// GET /api/v1/museums/{id}/shares
+func (h *ShareHandler) List(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	tokens, err := h.shareSvc.ListTokens(r.Context(), museumID, callerID)
+	if err != nil {
+		h.logError(r, "failed to list share tokens", err, slog.Int("museumId", museumID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+// Revoke は共有リンクを無効化する（所有者のみ）
+// DELETE /api/v1/museums/{id}/shares/{shareId}
+func (h *ShareHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	tokenID, err := parsePositiveIntParam(r, "shareId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.shareSvc.RevokeToken(r.Context(), museumID, tokenID, callerID); err != nil {
+		h.logError(r, "failed to revoke share token", err, slog.Int("museumId", museumID), slog.Int("shareId", tokenID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Open は共有リンクからミュージアムと展示作品を取得する
+// GET /api/v1/share/{token}
+func (h *ShareHandler) Open(w http.ResponseWriter, r *http.Request) {
+	callerID, err := parseCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	share, err := h.shareSvc.Resolve(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	museum, err := h.museumSvc.GetSharedMuseum(r.Context(), share.MuseumID, callerID)
+	if err != nil {
+		h.logError(r, "failed to get shared museum", err, slog.Int("museumId", share.MuseumID))
+		HandleError(w, err)
+		return
+	}
+
+	artworks, err := h.artworkSvc.ListSharedArtworks(r.Context(), share.MuseumID)
+	if err != nil {
+		h.logError(r, "failed to list shared artworks", err, slog.Int("museumId", share.MuseumID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, domain.SharedMuseumResponse{
+		Museum:   *museum,
+		Artworks: artworks,
+		Scope:    share.Scope,
+	})
+}
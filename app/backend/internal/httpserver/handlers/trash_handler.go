@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type TrashHandler struct {
+	log      *slog.Logger
+	trashSvc *service.TrashService
+}
+
+func NewTrashHandler(log *slog.Logger, trashSvc *service.TrashService) *TrashHandler {
+	return &TrashHandler{log: log, trashSvc: trashSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *TrashHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// List は呼び出しユーザーのゴミ箱を取得する
+// GET /api/v1/trash
+func (h *TrashHandler) List(w http.ResponseWriter, r *http.Request) {
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	trash, err := h.trashSvc.ListTrash(r.Context(), callerID)
+	if err != nil {
+		h.logError(r, "failed to list trash", err, slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, trash)
+}
+
+// RestoreMuseum はゴミ箱のミュージアムを元に戻す（owner役割のみ）
+// POST /api/v1/trash/museums/{id}/restore
+func (h *TrashHandler) RestoreMuseum(w http.ResponseWriter, r *http.Request) {
+	id, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	museum, err := h.trashSvc.RestoreMuseum(r.Context(), id, callerID)
+	if err != nil {
+		h.logError(r, "failed to restore museum", err, slog.Int("id", id), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, museum)
+}
+
+// RestoreArtwork はゴミ箱の展示作品を元に戻す（所有者・編集者のみ）
+// POST /api/v1/trash/museums/{id}/artworks/{objectId}/restore
+func (h *TrashHandler) RestoreArtwork(w http.ResponseWriter, r *http.Request) {
+	museumID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	objectID, err := parsePositiveIntParam(r, "objectId")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	if err := h.trashSvc.RestoreArtwork(r.Context(), museumID, objectID, callerID); err != nil {
+		h.logError(r, "failed to restore artwork", err, slog.Int("museumId", museumID), slog.Int("objectId", objectID))
+		HandleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/logger"
+	"backend/internal/service"
+)
+
+type UserHandler struct {
+	log       *slog.Logger
+	followSvc *service.FollowService
+}
+
+func NewUserHandler(log *slog.Logger, followSvc *service.FollowService) *UserHandler {
+	return &UserHandler{log: log, followSvc: followSvc}
+}
+
+// logError はリクエスト単位のロガー（リクエストID付き）でエラーログを出力するヘルパーメソッド
+func (h *UserHandler) logError(r *http.Request, message string, err error, attrs ...slog.Attr) {
+	args := []any{slog.String("error", err.Error())}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	logger.FromContext(r.Context(), h.log).Error(message, args...)
+}
+
+// Follow は指定ユーザーをフォローする（冪等）
+// POST /api/v1/users/{id}/follow
+func (h *UserHandler) Follow(w http.ResponseWriter, r *http.Request) {
+	h.changeFollow(w, r, h.followSvc.Follow, "failed to follow user")
+}
+
+// Unfollow は指定ユーザーのフォローを解除する（冪等）
+// DELETE /api/v1/users/{id}/follow
+func (h *UserHandler) Unfollow(w http.ResponseWriter, r *http.Request) {
+	h.changeFollow(w, r, h.followSvc.Unfollow, "failed to unfollow user")
+}
+
+// Followers は指定ユーザーのフォロワー一覧を取得する
+// GET /api/v1/users/{id}/followers?limit=20&offset=0
+func (h *UserHandler) Followers(w http.ResponseWriter, r *http.Request) {
+	h.listFollows(w, r, h.followSvc.ListFollowers, "failed to list followers")
+}
+
+// Following は指定ユーザーがフォローしているユーザーの一覧を取得する
+// GET /api/v1/users/{id}/following?limit=20&offset=0
+func (h *UserHandler) Following(w http.ResponseWriter, r *http.Request) {
+	h.listFollows(w, r, h.followSvc.ListFollowing, "failed to list following")
+}
+
+// changeFollow はフォロー・フォロー解除の共通処理
+func (h *UserHandler) changeFollow(w http.ResponseWriter, r *http.Request, change func(context.Context, int, int) (*domain.FollowResponse, error), message string) {
+	userID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	callerID, err := requireCallerID(r)
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	result, err := change(r.Context(), callerID, userID)
+	if err != nil {
+		h.logError(r, message, err, slog.Int("userId", userID), slog.Int("callerId", callerID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// listFollows はフォロー一覧取得の共通処理
+func (h *UserHandler) listFollows(w http.ResponseWriter, r *http.Request, list func(context.Context, int, int, int) (*domain.FollowListResponse, error), message string) {
+	userID, err := parsePositiveIntParam(r, "id")
+	if err != nil {
+		HandleError(w, err)
+		return
+	}
+
+	limit := parseOptionalIntQuery(r, "limit", 20)
+	offset := parseOptionalIntQuery(r, "offset", 0)
+
+	result, err := list(r.Context(), userID, limit, offset)
+	if err != nil {
+		h.logError(r, message, err, slog.Int("userId", userID))
+		HandleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
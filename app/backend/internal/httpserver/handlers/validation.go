@@ -1,12 +1,12 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
+	"backend/internal/service"
 	"github.com/go-chi/chi/v5"
-	"backend/internal/domain"
 )
 
 // parsePositiveIntParam parses a URL parameter as a positive integer
@@ -57,29 +57,49 @@ func parseOptionalIntQuery(r *http.Request, paramName string, defaultValue int)
 	return defaultValue
 }
 
-// decodeJSONBody decodes JSON request body
-func decodeJSONBody(r *http.Request, dst interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
-		return ErrInvalidRequestBody
+// callerIDHeader は呼び出しユーザーのIDを渡すヘッダー
+// 認証導入までの暫定措置として、フロントエンドがログイン中のユーザーIDを付与する
+const callerIDHeader = "X-User-ID"
+
+// parseCallerID parses the caller's user ID from the X-User-ID header.
+// Returns 0 when the header is absent (anonymous caller).
+func parseCallerID(r *http.Request) (int, error) {
+	idStr := r.Header.Get(callerIDHeader)
+	if idStr == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		return 0, NewBadRequestError("invalid " + callerIDHeader + " header")
 	}
-	return nil
+	return id, nil
 }
 
-// validateMuseumCreateRequest validates museum create request
-func validateMuseumCreateRequest(req domain.MuseumCreateRequest) error {
-	if req.UserID <= 0 {
-		return NewBadRequestError("userId is required")
+// requireCallerID parses the caller's user ID and rejects anonymous callers.
+func requireCallerID(r *http.Request) (int, error) {
+	id, err := parseCallerID(r)
+	if err != nil {
+		return 0, err
 	}
-	if req.Name == "" {
-		return NewBadRequestError("name is required")
+	if id == 0 {
+		return 0, NewBadRequestError(callerIDHeader + " header is required")
 	}
-	return nil
+	return id, nil
 }
 
-// validateMuseumTitleUpdateRequest validates museum title update request
-func validateMuseumTitleUpdateRequest(req domain.MuseumTitleUpdateRequest) error {
-	if req.Title == "" {
-		return NewBadRequestError("title is required")
+// resolveShareToken checks the optional ?token= query against the museum.
+// Returns true when a valid share link for the museum was presented.
+func resolveShareToken(r *http.Request, shareSvc *service.ShareService, museumID int) (bool, error) {
+	token := r.URL.Query().Get("token")
+	if token == "" || shareSvc == nil {
+		return false, nil
+	}
+	share, err := shareSvc.Resolve(r.Context(), token)
+	if err != nil {
+		return false, err
 	}
-	return nil
-}
\ No newline at end of file
+	if share.MuseumID != museumID {
+		return false, errors.New("share link not found")
+	}
+	return true, nil
+}
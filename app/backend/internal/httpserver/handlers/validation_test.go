@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+	"backend/internal/service"
+)
+
+// fakeShareTokenRepo は生のトークンからFindByHashで引けるShareTokenRepository
+type fakeShareTokenRepo struct {
+	repository.ShareTokenRepository
+	tokens map[string]domain.ShareToken // トークンのハッシュ → 共有リンク
+}
+
+func (r fakeShareTokenRepo) FindByHash(_ context.Context, tokenHash string) (*domain.ShareToken, error) {
+	if t, ok := r.tokens[tokenHash]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func shareTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestResolveShareToken(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour)
+	shareSvc := service.NewShareService(fakeShareTokenRepo{tokens: map[string]domain.ShareToken{
+		shareTokenHash("museum-10"): {ID: 1, MuseumID: 10},
+		shareTokenHash("revoked"):   {ID: 2, MuseumID: 10, RevokedAt: &revokedAt},
+	}}, nil)
+
+	tests := []struct {
+		name       string
+		query      string
+		museumID   int
+		wantShared bool
+		wantStatus int // 0: エラーなし
+	}{
+		{"no token", "", 10, false, 0},
+		{"matching museum", "?token=museum-10", 10, true, 0},
+		// 他のミュージアムの共有リンクでは閲覧できない
+		{"mismatched museum", "?token=museum-10", 11, false, http.StatusNotFound},
+		{"unknown token", "?token=unknown", 10, false, http.StatusNotFound},
+		{"revoked token", "?token=revoked", 10, false, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/museums/1"+tt.query, nil)
+		shared, err := resolveShareToken(r, shareSvc, tt.museumID)
+		if shared != tt.wantShared {
+			t.Errorf("%s: shared = %v, want %v", tt.name, shared, tt.wantShared)
+		}
+		if tt.wantStatus == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		w := httptest.NewRecorder()
+		HandleError(w, err)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d (%v)", tt.name, w.Code, tt.wantStatus, err)
+		}
+	}
+}
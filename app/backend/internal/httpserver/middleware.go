@@ -0,0 +1,240 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"backend/internal/logger"
+	"backend/internal/metrics"
+	"backend/internal/tracing"
+)
+
+// requestIDHeader はリクエストを識別するIDのヘッダー
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength は受け付けるX-Request-IDの最大長。これを超えるIDは採番し直す
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// requestIDFromContext はコンテキストに格納されたリクエストIDを返す
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// traceRequest はリクエストごとにサーバースパンを作る
+// 上流からtraceparentヘッダーが渡されていればそのトレースの子スパンになる
+// スパン名はルーティング後にchiのルートパターンで付け直す（例: "GET /api/v1/museums/{id}"）
+func traceRequest(next http.Handler) http.Handler {
+	named := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route := rctx.RoutePattern()
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + route)
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+	})
+	return otelhttp.NewHandler(named, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+}
+
+// requestContext はリクエストIDを決めてレスポンスヘッダーに設定し、
+// リクエストIDを付けたロガーをコンテキストに格納する（ハンドラーはlogger.FromContextで取り出す）
+// クライアントやロードバランサーから妥当なX-Request-IDが渡されていればそれを引き継ぐ
+// トレースを記録している場合はtraceIdもログに付け、トレースとログを突き合わせられるようにする
+func requestContext(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestIDHeader)
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			w.Header().Set(requestIDHeader, id)
+
+			ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+			reqLog := log.With(slog.String("requestId", id))
+			if traceID := tracing.TraceID(ctx); traceID != "" {
+				reqLog = reqLog.With(slog.String("traceId", traceID))
+			}
+			ctx = logger.WithContext(ctx, reqLog)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// validRequestID は引き継いでよいリクエストIDか（空でなく、長すぎず、表示可能なASCII文字のみ）を返す
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID はランダムな32桁の16進数のリクエストIDを作る
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// accessLog はリクエストごとにメソッド、ルートパターン、ステータス、レスポンスのバイト数、処理時間を記録する
+// 5xxはError、それ以外はInfoで出力する
+func accessLog(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				level := slog.LevelInfo
+				if status >= http.StatusInternalServerError {
+					level = slog.LevelError
+				}
+
+				route := ""
+				if rctx := chi.RouteContext(r.Context()); rctx != nil {
+					route = rctx.RoutePattern()
+				}
+
+				logger.FromContext(r.Context(), log).LogAttrs(r.Context(), level, "http request",
+					slog.String("method", r.Method),
+					slog.String("route", route),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.Float64("latencyMs", float64(time.Since(start).Microseconds())/1000),
+				)
+			}()
+
+			next.ServeHTTP(ww, r)
+		})
+	}
+}
+
+// measureRequest はリクエストの処理時間をchiのルートパターン・メソッド・ステータスごとにヒストグラムへ記録する
+// どのルートにも一致しないリクエストはroute="unmatched"にまとめ、ラベルの種類が増えすぎないようにする
+func measureRequest(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			metrics.HTTPRequestDuration.WithLabelValues(route, r.Method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// problem はRFC 9457（application/problem+json）形式のエラーレスポンス
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// recoverPanic はハンドラーのpanicをログに記録し、500のproblemレスポンスに変換する
+// 接続を切らずに応答するため、クライアントはX-Request-IDでログと突き合わせられる
+func recoverPanic(log *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// http.ErrAbortHandlerはレスポンスを中断するための意図的なpanic
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				logger.FromContext(r.Context(), log).Error("panic recovered",
+					slog.Any("panic", rec),
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("stack", string(debug.Stack())),
+				)
+
+				// 既にレスポンスを書き始めている場合はステータスを変更できない
+				if ww, ok := w.(middleware.WrapResponseWriter); ok && ww.Status() != 0 {
+					return
+				}
+				writeProblem(w, r, http.StatusInternalServerError, "internal server error")
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeProblem はproblemレスポンスを書き込む
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestIDFromContext(r.Context()),
+	})
+}
+
+// limitRequestBody はリクエストボディをlimitバイトまでに制限する
+// limitが0以下の場合は制限しない。ファイルのアップロード（multipart/form-data）は
+// JSONより大きな上限が必要なため対象外とし、受け付けるハンドラーがそれぞれ制限する
+func limitRequestBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if r.Body != nil && r.Body != http.NoBody && mediaType != "multipart/form-data" {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
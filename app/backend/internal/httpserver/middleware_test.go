@@ -0,0 +1,173 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"backend/internal/logger"
+)
+
+// newMiddlewareTestRouter はNewRouterと同じ順でミドルウェアを組んだテスト用ルーター
+func newMiddlewareTestRouter(buf *bytes.Buffer) http.Handler {
+	log := slog.New(slog.NewJSONHandler(buf, nil))
+	r := chi.NewRouter()
+	r.Use(requestContext(log))
+	r.Use(accessLog(log))
+	r.Use(recoverPanic(log))
+	r.Get("/things/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context(), nil).Info("inside handler")
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("hello"))
+	})
+	r.Get("/panic", func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+	return r
+}
+
+// logLines はJSONログを1行ずつ読み取る
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestRequestContextPropagatesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	req.Header.Set(requestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	newMiddlewareTestRouter(&buf).ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(requestIDHeader); got != "abc-123" {
+		t.Fatalf("expected propagated request ID, got %q", got)
+	}
+
+	lines := logLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("expected handler log and access log, got %d lines", len(lines))
+	}
+	for _, entry := range lines {
+		if entry["requestId"] != "abc-123" {
+			t.Errorf("log line without request ID: %v", entry)
+		}
+	}
+
+	access := lines[1]
+	if access["route"] != "/things/{id}" || access["method"] != "GET" {
+		t.Errorf("unexpected route or method: %v", access)
+	}
+	if access["status"] != float64(http.StatusTeapot) || access["bytes"] != float64(5) {
+		t.Errorf("unexpected status or bytes: %v", access)
+	}
+	if _, ok := access["latencyMs"]; !ok {
+		t.Errorf("access log without latency: %v", access)
+	}
+}
+
+func TestRequestContextGeneratesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	req.Header.Set(requestIDHeader, "has space")
+	rec := httptest.NewRecorder()
+	newMiddlewareTestRouter(&buf).ServeHTTP(rec, req)
+
+	got := rec.Header().Get(requestIDHeader)
+	if len(got) != 32 {
+		t.Fatalf("expected generated 32-char request ID, got %q", got)
+	}
+}
+
+func TestRecoverPanicReturnsProblem(t *testing.T) {
+	var buf bytes.Buffer
+	rec := httptest.NewRecorder()
+	newMiddlewareTestRouter(&buf).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected problem content type, got %q", ct)
+	}
+
+	var body problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid problem body: %v", err)
+	}
+	if body.Status != http.StatusInternalServerError || body.RequestID != rec.Header().Get(requestIDHeader) || body.Instance != "/panic" {
+		t.Fatalf("unexpected problem body: %+v", body)
+	}
+
+	lines := logLines(t, &buf)
+	if lines[0]["msg"] != "panic recovered" || lines[0]["panic"] != "boom" {
+		t.Errorf("expected panic log, got %v", lines[0])
+	}
+	if last := lines[len(lines)-1]; last["status"] != float64(http.StatusInternalServerError) || last["level"] != "ERROR" {
+		t.Errorf("expected access log with 500, got %v", last)
+	}
+}
+
+func TestTraceRequestContinuesIncomingTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	r := chi.NewRouter()
+	r.Use(traceRequest)
+	r.Use(requestContext(log))
+	r.Get("/things/{id}", func(w http.ResponseWriter, r *http.Request) {
+		logger.FromContext(r.Context(), nil).Info("inside handler")
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/things/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /things/{id}" {
+		t.Fatalf("expected span named after the route, got %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Fatalf("expected incoming trace ID, got %q", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Fatalf("expected incoming span as parent, got %q", got)
+	}
+
+	lines := logLines(t, &buf)
+	if lines[0]["traceId"] != traceID {
+		t.Fatalf("expected traceId in handler log, got %v", lines[0]["traceId"])
+	}
+}
@@ -0,0 +1,20 @@
+package httpserver
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec はNewRouterに登録する全ルートを記述したOpenAPI 3ドキュメント
+// ルートを追加・変更したときはopenapi.jsonも更新する（router_test.goで照合している）
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// serveOpenAPI はOpenAPIドキュメントを返す
+// GET /api/openapi.json
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openAPISpec)
+}
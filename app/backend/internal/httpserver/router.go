@@ -9,38 +9,107 @@ import (
     "github.com/go-chi/cors"
 
     "backend/internal/config"
+    "backend/internal/health"
     "backend/internal/httpserver/handlers"
+    "backend/internal/logger"
+    "backend/internal/metrics"
     "backend/internal/service"
 )
 
+// Services はルーターに登録するサービス群
+// DB未接続時などnilのサービスは対応するルートを登録しない
+type Services struct {
+    Item          *service.ItemService
+    Museum        *service.MuseumService
+    ArtworkSearch *service.ArtworkSearchService
+    Met           *service.MetService
+    Comment       *service.CommentService
+    Artwork       *service.ArtworkService
+    Follow        *service.FollowService
+    Activity      *service.ActivityService
+    Share         *service.ShareService
+    Member        *service.MemberService
+    Revision      *service.RevisionService
+    Trash         *service.TrashService
+    Image         *service.ImageService
+    Palette       *service.PaletteService
+    Layout        *service.LayoutService
+    Cover         *service.CoverService
+    Embed         *service.EmbedService
+    Export        *service.ExportService
+
+    // BlobFiles はローカルに保存したアップロードファイルを/blobs以下で返すハンドラー
+    // （S3互換ストレージを使う場合はnilで、ストレージから直接配信する）
+    BlobFiles http.Handler
+
+    // Readiness は/readyzで実行する確認（nilの場合は確認なしで常に成功する）
+    Readiness *health.Readiness
+}
+
 // NewRouter configures chi router, CORS, and registers routes.
-func NewRouter(cfg config.Config, log *slog.Logger, itemSvc *service.ItemService, museumSvc *service.MuseumService, artworkSearchSvc *service.ArtworkSearchService) http.Handler {
+func NewRouter(cfg config.Config, log *slog.Logger, svcs Services) http.Handler {
     r := chi.NewRouter()
 
+    // トレース・リクエストID・アクセスログ・panicの回復（CORSのプリフライトも記録する）
+    r.Use(traceRequest)
+    r.Use(requestContext(log))
+    r.Use(accessLog(log))
+    r.Use(measureRequest)
+    r.Use(recoverPanic(log))
+
     // CORS
     r.Use(cors.Handler(cors.Options{
         AllowedOrigins:   cfg.AllowedOrigins,
-        AllowedMethods:   []string{"GET", "POST", "PATCH", "OPTIONS"},
-        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-        ExposedHeaders:   []string{"Link"},
+        AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-User-ID", "If-Match", "If-None-Match", "X-Request-ID", "traceparent", "tracestate"},
+        ExposedHeaders:   []string{"Link", "ETag", "X-Request-ID"},
         AllowCredentials: false,
         MaxAge:           300,
     }))
 
+    // リクエストボディの上限（超えた場合はDecodeJSONBodyが413を返す）
+    r.Use(limitRequestBody(cfg.MaxRequestBodyBytes))
+
     // Health
     r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
         w.Header().Set("Content-Type", "application/json")
         _ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
     })
 
+    // 死活確認（プロセスが応答できるか）と受付可否（DB・マイグレーション・シャットダウン中か）
+    r.Get("/livez", health.Live)
+    readiness := svcs.Readiness
+    if readiness == nil {
+        readiness = health.NewReadiness()
+    }
+    r.Get("/readyz", readiness.ServeHTTP)
+
+    // OpenAPIドキュメント
+    r.Get("/api/openapi.json", serveOpenAPI)
+
+    // Prometheusメトリクス
+    r.Get("/metrics", metrics.Handler().ServeHTTP)
+
+    // アップロードされたファイル（キーはcovers/{museumId}/{name}の形）
+    if svcs.BlobFiles != nil {
+        r.Get("/blobs/covers/{museumId}/{name}", http.StripPrefix("/blobs", svcs.BlobFiles).ServeHTTP)
+    }
+
+    // 共有ページ（Open Graph・Twitterカード）とoEmbed
+    if svcs.Embed != nil {
+        embedHandler := handlers.NewEmbedHandler(log, svcs.Embed)
+        r.Get("/m/{id}", embedHandler.Page)
+        r.Get("/oembed", embedHandler.OEmbed)
+    }
+
     // API routes
     r.Route("/api/v1", func(api chi.Router) {
         // GET /items -> list
         api.Get("/items", func(w http.ResponseWriter, r *http.Request) {
-            items, err := itemSvc.List()
+            items, err := svcs.Item.List()
             if err != nil {
                 handlers.RespondError(w, http.StatusInternalServerError, "failed to list items")
-                log.Error("list items failed", slog.String("error", err.Error()))
+                logger.FromContext(r.Context(), log).Error("list items failed", slog.String("error", err.Error()))
                 return
             }
             handlers.RespondJSON(w, http.StatusOK, items)
@@ -53,11 +122,11 @@ func NewRouter(cfg config.Config, log *slog.Logger, itemSvc *service.ItemService
 
         api.Post("/items", func(w http.ResponseWriter, r *http.Request) {
             var req createReq
-            if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-                handlers.RespondError(w, http.StatusBadRequest, "invalid JSON body")
+            if err := handlers.DecodeJSONBody(r, &req); err != nil {
+                handlers.HandleError(w, err)
                 return
             }
-            item, err := itemSvc.Create(req.Name)
+            item, err := svcs.Item.Create(req.Name)
             if err != nil {
                 handlers.RespondError(w, http.StatusBadRequest, err.Error())
                 return
@@ -65,19 +134,36 @@ func NewRouter(cfg config.Config, log *slog.Logger, itemSvc *service.ItemService
             handlers.RespondJSON(w, http.StatusCreated, item)
         })
 
-        // Met API service and handler
-        metSvc := service.NewMetService()
-        metHandler := handlers.NewMetHandler(log, metSvc)
+        // Met API handler
+        if svcs.Met != nil {
+            metHandler := handlers.NewMetHandler(log, svcs.Met)
 
-        // idから絵画情報取得
-        api.Get("/met/objects/{id}", metHandler.GetObjectByID)
+            // idから絵画情報取得（複数まとめての取得は ?ids=1,2,3）
+            api.Get("/met/objects", metHandler.GetObjectsByIDs)
+            api.Get("/met/objects/{id}", metHandler.GetObjectByID)
+        }
+
+        // 作品画像（縮小・形式変換、canvas用にどのオリジンからも読み込める）
+        if svcs.Image != nil {
+            imageHandler := handlers.NewImageHandler(log, svcs.Image)
+            api.Get("/images/{objectId}", imageHandler.Get)
+        }
+
+        // 作品画像の代表色（未計算ならその場で計算する）
+        if svcs.Palette != nil {
+            paletteHandler := handlers.NewPaletteHandler(log, svcs.Palette)
+            api.Get("/met/objects/{id}/palette", paletteHandler.Get)
+        }
 
         // Museum API
-        if museumSvc != nil {
-            museumHandler := handlers.NewMuseumHandler(log, museumSvc)
+        if svcs.Museum != nil {
+            museumHandler := handlers.NewMuseumHandler(log, svcs.Museum, svcs.Share, cfg.TrustedProxies)
             
             // 1. 公開ミュージアム取得（自分以外）
             api.Get("/museums", museumHandler.GetPublicMuseumsExceptUser)
+
+            // ミュージアム検索（名前・説明文）
+            api.Get("/museums/search", museumHandler.Search)
             
             // 2. ミュージアム詳細取得
             api.Get("/museums/{id}", museumHandler.GetMuseumByID)
@@ -87,11 +173,116 @@ func NewRouter(cfg config.Config, log *slog.Logger, itemSvc *service.ItemService
             
             // 4. ミュージアム作成
             api.Post("/museums", museumHandler.Create)
+
+            // いいね・いいね取り消し
+            api.Post("/museums/{id}/like", museumHandler.Like)
+            api.Delete("/museums/{id}/like", museumHandler.Unlike)
+
+            // 公開設定の変更
+            api.Patch("/museums/{id}/visibility", museumHandler.UpdateVisibility)
+
+            // 複製（フォーク）
+            api.Post("/museums/{id}/fork", museumHandler.Fork)
+
+            // 削除（ゴミ箱に移す）
+            api.Delete("/museums/{id}", museumHandler.Delete)
+        }
+
+        // カバー画像のアップロード
+        if svcs.Cover != nil {
+            coverHandler := handlers.NewCoverHandler(log, svcs.Cover)
+            api.Post("/museums/{id}/cover", coverHandler.Upload)
+        }
+
+        // ミュージアムの展示作品
+        if svcs.Artwork != nil {
+            artworkHandler := handlers.NewArtworkHandler(log, svcs.Artwork, svcs.Share)
+            api.Get("/museums/{id}/artworks", artworkHandler.List)
+            api.Post("/museums/{id}/artworks", artworkHandler.Add)
+            api.Delete("/museums/{id}/artworks/{objectId}", artworkHandler.Remove)
+        }
+
+        // 部屋の配置と合成画像
+        if svcs.Layout != nil {
+            layoutHandler := handlers.NewLayoutHandler(log, svcs.Layout, svcs.Share)
+            api.Get("/museums/{id}/layout", layoutHandler.Get)
+            api.Put("/museums/{id}/layout", layoutHandler.Update)
+            api.Get("/museums/{id}/render.png", layoutHandler.Render)
+        }
+
+        // 静的サイトとしての書き出し
+        if svcs.Export != nil {
+            exportHandler := handlers.NewExportHandler(log, svcs.Export, svcs.Share)
+            api.Get("/museums/{id}/export.zip", exportHandler.Export)
+        }
+
+        // 共有リンク
+        if svcs.Share != nil && svcs.Museum != nil && svcs.Artwork != nil {
+            shareHandler := handlers.NewShareHandler(log, svcs.Share, svcs.Museum, svcs.Artwork)
+            api.Post("/museums/{id}/shares", shareHandler.Create)
+            api.Get("/museums/{id}/shares", shareHandler.List)
+            api.Delete("/museums/{id}/shares/{shareId}", shareHandler.Revoke)
+            api.Get("/share/{token}", shareHandler.Open)
+        }
+
+        // 共同キュレーター（メンバー・招待）
+        if svcs.Member != nil {
+            memberHandler := handlers.NewMemberHandler(log, svcs.Member)
+            api.Get("/museums/{id}/members", memberHandler.List)
+            api.Patch("/museums/{id}/members/{userId}", memberHandler.UpdateRole)
+            api.Delete("/museums/{id}/members/{userId}", memberHandler.Remove)
+            api.Post("/museums/{id}/invitations", memberHandler.Invite)
+            api.Get("/museums/{id}/invitations", memberHandler.ListMuseumInvitations)
+            api.Delete("/museums/{id}/invitations/{invitationId}", memberHandler.RevokeInvitation)
+            api.Get("/invitations", memberHandler.ListMine)
+            api.Post("/invitations/{invitationId}/accept", memberHandler.Accept)
+            api.Post("/invitations/{invitationId}/decline", memberHandler.Decline)
+        }
+
+        // ゴミ箱
+        if svcs.Trash != nil {
+            trashHandler := handlers.NewTrashHandler(log, svcs.Trash)
+            api.Get("/trash", trashHandler.List)
+            api.Post("/trash/museums/{id}/restore", trashHandler.RestoreMuseum)
+            api.Post("/trash/museums/{id}/artworks/{objectId}/restore", trashHandler.RestoreArtwork)
+        }
+
+        // 変更履歴
+        if svcs.Revision != nil {
+            revisionHandler := handlers.NewRevisionHandler(log, svcs.Revision)
+            api.Get("/museums/{id}/revisions", revisionHandler.List)
+            api.Get("/museums/{id}/revisions/diff", revisionHandler.Diff)
+            api.Get("/museums/{id}/revisions/{revisionId}", revisionHandler.Get)
+            api.Post("/museums/{id}/revisions/{revisionId}/restore", revisionHandler.Restore)
+        }
+
+        // コメント
+        if svcs.Comment != nil {
+            commentHandler := handlers.NewCommentHandler(log, svcs.Comment)
+            api.Get("/museums/{id}/comments", commentHandler.List)
+            api.Post("/museums/{id}/comments", commentHandler.Create)
+            api.Patch("/comments/{commentId}", commentHandler.Update)
+            api.Delete("/comments/{commentId}", commentHandler.Delete)
+        }
+
+        // フォロー
+        if svcs.Follow != nil {
+            userHandler := handlers.NewUserHandler(log, svcs.Follow)
+            api.Post("/users/{id}/follow", userHandler.Follow)
+            api.Delete("/users/{id}/follow", userHandler.Unfollow)
+            api.Get("/users/{id}/followers", userHandler.Followers)
+            api.Get("/users/{id}/following", userHandler.Following)
+        }
+
+        // フォロー中ユーザーのアクティビティフィード
+        if svcs.Activity != nil {
+            feedHandler := handlers.NewFeedHandler(log, svcs.Activity)
+            api.Get("/feed", feedHandler.GetFeed)
         }
 
         // 5. 作品検索（MET API）
-        if artworkSearchSvc != nil {
-            searchHandler := handlers.NewArtworkSearchHandler(log, artworkSearchSvc)
+        if svcs.ArtworkSearch != nil {
+            searchHandler := handlers.NewArtworkSearchHandler(log, svcs.ArtworkSearch)
             api.Get("/search/artworks", searchHandler.SearchArtworks)
         }
     })
@@ -0,0 +1,158 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"backend/internal/config"
+	"backend/internal/metclient"
+	"backend/internal/service"
+)
+
+// allServices は全ルートを登録させるためにすべてのサービスを用意する
+// ルート一覧の取得だけなのでリポジトリはnilでよい
+func allServices() Services {
+	access := service.NewMuseumAccess(nil, nil)
+	metClient := metclient.New(metclient.DefaultConfig())
+	metSvc := service.NewMetService(metClient)
+	imageSvc := service.NewImageService(metSvc, metClient, nil, nil)
+	return Services{
+		Item:          service.NewItemService(nil),
+		Museum:        service.NewMuseumService(nil, nil, access, nil, nil),
+		ArtworkSearch: service.NewArtworkSearchService(metClient, nil),
+		Met:           metSvc,
+		Comment:       service.NewCommentService(nil, access, nil),
+		Artwork:       service.NewArtworkService(nil, nil, access, nil, nil, nil),
+		Follow:        service.NewFollowService(nil),
+		Activity:      service.NewActivityService(nil, nil),
+		Share:         service.NewShareService(nil, access),
+		Member:        service.NewMemberService(nil, access),
+		Revision:      service.NewRevisionService(nil, access, nil),
+		Trash:         service.NewTrashService(nil, nil, access, nil, 0, nil),
+		Image:         imageSvc,
+		Palette:       service.NewPaletteService(nil, imageSvc, nil),
+		Layout:        service.NewLayoutService(nil, nil, nil, access, imageSvc, nil, nil, nil),
+		Cover:         service.NewCoverService(nil, access, nil, nil, 0),
+		Embed:         service.NewEmbedService(nil, nil, "http://localhost:8080", "http://localhost:5173"),
+		Export:        service.NewExportService(nil, nil, access, metSvc, imageSvc, nil),
+		BlobFiles:     http.NotFoundHandler(),
+	}
+}
+
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRouter(config.Config{}, log, allServices())
+}
+
+// loadSpec は埋め込んだOpenAPIドキュメントを読み込む
+func loadSpec(t *testing.T) map[string]map[string]json.RawMessage {
+	t.Helper()
+	var doc struct {
+		OpenAPI string                                `json:"openapi"`
+		Paths   map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("expected OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+	return doc.Paths
+}
+
+func TestRouterRoutesMatchOpenAPISpec(t *testing.T) {
+	routes, ok := newTestRouter(t).(chi.Routes)
+	if !ok {
+		t.Fatalf("router does not implement chi.Routes")
+	}
+
+	registered := map[string]bool{}
+	err := chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		registered[strings.ToUpper(method)+" "+route] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk routes: %v", err)
+	}
+
+	documented := map[string]bool{}
+	for path, item := range loadSpec(t) {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	var missing, extra []string
+	for route := range registered {
+		if !documented[route] {
+			missing = append(missing, route)
+		}
+	}
+	for route := range documented {
+		if !registered[route] {
+			extra = append(extra, route)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+
+	if len(missing) > 0 {
+		t.Errorf("routes missing from openapi.json:\n  %s", strings.Join(missing, "\n  "))
+	}
+	if len(extra) > 0 {
+		t.Errorf("openapi.json documents routes that NewRouter does not register:\n  %s", strings.Join(extra, "\n  "))
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("expected application/json, got %q", ct)
+	}
+	if rec.Body.String() != string(openAPISpec) {
+		t.Fatalf("response body does not match embedded spec")
+	}
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	router := newTestRouter(t)
+
+	// ルートパターンでラベル付けされることを確認するため、先に1件リクエストしておく
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		`http_request_duration_seconds_count{method="GET",route="/health",status="200"}`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output does not contain %q", want)
+		}
+	}
+}
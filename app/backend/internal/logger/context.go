@@ -0,0 +1,21 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+type contextKey struct{}
+
+// WithContext はリクエスト単位のロガーをコンテキストに格納する
+func WithContext(ctx context.Context, log *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext はコンテキストに格納されたロガーを返す。格納されていなければfallbackを返す
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if log, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return log
+	}
+	return fallback
+}
@@ -0,0 +1,90 @@
+package metclient
+
+import (
+	"sync"
+	"time"
+
+	"backend/internal/metrics"
+)
+
+// ブレーカーの状態
+type breakerState int
+
+const (
+	stateClosed   breakerState = iota // 通常どおりリクエストを送る
+	stateOpen                         // リクエストを送らずに失敗させる
+	stateHalfOpen                     // 試しに1件だけ送り、結果で閉じるか開き直すか決める
+)
+
+// breaker は連続失敗回数で開閉するサーキットブレーカー
+// thresholdが0以下の場合は常に閉じたまま
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool // 半開状態で試しのリクエストを送っている最中か
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow はリクエストを送ってよいかを返す
+// 開いてからcooldownが過ぎていれば半開にして、1件だけ許可する
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = stateHalfOpen
+		b.probing = true
+		return true
+	case stateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// record はリクエストの結果を記録する
+// 閉じた状態で連続失敗がthresholdに達するか、半開状態の試しのリクエストが失敗すると開く
+func (b *breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		if b.state != stateClosed {
+			b.state = stateClosed
+			metrics.METCircuitOpen.Set(0)
+		}
+		return
+	}
+
+	b.failures++
+	if b.threshold > 0 && (b.state == stateHalfOpen || b.failures >= b.threshold) {
+		b.state = stateOpen
+		b.openedAt = b.now()
+		metrics.METCircuitOpen.Set(1)
+	}
+}
+
+// cancel は結果のわからなかったリクエストを取り消し、半開状態なら次の試しを許可する
+func (b *breaker) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
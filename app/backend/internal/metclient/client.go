@@ -0,0 +1,257 @@
+// Package metclient はMET Collection APIへのリクエストに使う共有HTTPクライアントを提供する
+// 試行ごとのタイムアウト、ジッター付き指数バックオフでの再試行（Retry-Afterを優先）、
+// サーキットブレーカー、クライアント側のレート制限をまとめて扱う
+package metclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"backend/internal/metrics"
+	"backend/internal/tracing"
+)
+
+// ErrCircuitOpen はMET APIの障害が続いていて、リクエストを送らずに失敗させた場合に返される
+var ErrCircuitOpen = errors.New("MET API circuit breaker is open")
+
+// Config はClientの設定
+type Config struct {
+	MaxAttempts    int           // 最初のリクエストを含む最大試行回数
+	AttemptTimeout time.Duration // 1回の試行（レスポンスボディの読み取りを含む）のタイムアウト
+	BaseBackoff    time.Duration // 再試行の待ち時間の基準（試行ごとに2倍）
+	MaxBackoff     time.Duration // 再試行の待ち時間の上限
+	MaxRetryAfter  time.Duration // これより長いRetry-Afterが返された場合は再試行しない
+
+	RateLimit float64 // 1秒あたりのリクエスト数の上限（0以下で無制限）
+	Burst     int     // 一度に送れるリクエスト数
+
+	BreakerThreshold int           // ブレーカーを開く連続失敗回数
+	BreakerCooldown  time.Duration // ブレーカーを開いてから試しにリクエストを送るまでの時間
+
+	Transport http.RoundTripper // 省略時はトレースとメトリクスを記録するトランスポート
+}
+
+// DefaultConfig は既定の設定を返す
+// METは1秒あたり80リクエストまでとしているため、余裕を持って50に制限する
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:      3,
+		AttemptTimeout:   10 * time.Second,
+		BaseBackoff:      200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+		MaxRetryAfter:    30 * time.Second,
+		RateLimit:        50,
+		Burst:            10,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// Client はMET APIへのリクエストを送るHTTPクライアント
+// MetServiceとArtworkSearchServiceで共有し、レート制限とブレーカーの状態をプロセス全体でまとめる
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	limiter *rate.Limiter
+	breaker *breaker
+}
+
+// New は設定に従ったClientを作成する
+func New(cfg Config) *Client {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	transport := cfg.Transport
+	if transport == nil {
+		transport = tracing.Transport(metrics.METTransport{})
+	}
+	limit := rate.Inf
+	if cfg.RateLimit > 0 {
+		limit = rate.Limit(cfg.RateLimit)
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Transport: transport},
+		limiter: rate.NewLimiter(limit, burst),
+		breaker: newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// Get はurlにGETリクエストを送る
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Do はリクエストを送り、通信エラー・429・5xxの場合は再試行する
+// 再試行はGET/HEADだけで、試行回数を使い切った場合は最後のレスポンス（またはエラー）を返す
+// レスポンスボディを閉じるまでは試行のタイムアウトが有効なので、呼び出し側は必ずBodyを閉じること
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	endpoint := metrics.METEndpoint(req.URL.Path)
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+
+	for attempt := 1; ; attempt++ {
+		if !c.breaker.allow() {
+			metrics.METErrors.WithLabelValues(endpoint, "circuit_open").Inc()
+			return nil, ErrCircuitOpen
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			c.breaker.cancel()
+			return nil, err
+		}
+
+		resp, err := c.attempt(req)
+		if ctx.Err() != nil {
+			// 呼び出し側の都合で中断した場合はMETの状態がわからないので記録しない
+			c.breaker.cancel()
+		} else {
+			c.breaker.record(isFailure(resp, err))
+		}
+
+		last := !idempotent || attempt >= c.cfg.MaxAttempts || ctx.Err() != nil
+		if last || !retryable(resp, err) {
+			return resp, err
+		}
+
+		wait := c.backoff(attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if after > c.cfg.MaxRetryAfter {
+					return resp, nil
+				}
+				wait = after
+			}
+			drain(resp)
+		}
+		metrics.METRetries.WithLabelValues(endpoint, retryReason(resp)).Inc()
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt はタイムアウト付きで1回だけリクエストを送る
+// タイムアウトはレスポンスボディを閉じるまで有効にする
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if c.cfg.AttemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.AttemptTimeout)
+	}
+	resp, err := c.http.Do(req.Clone(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff は試行回数に応じた待ち時間を返す（0から上限までの一様乱数のフルジッター）
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.BaseBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.cfg.MaxBackoff {
+		ceiling = c.cfg.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// retryable は再試行すべき結果か（通信エラー、429、502/503/504と500）を返す
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isFailure はブレーカーの失敗として数える結果か（通信エラーと5xx）を返す
+// 429はMET側が正常に応答しているので失敗に数えない
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryReason は再試行の理由をメトリクスのラベルにする（network または ステータスコード）
+func retryReason(resp *http.Response) string {
+	if resp == nil {
+		return "network"
+	}
+	return strconv.Itoa(resp.StatusCode)
+}
+
+// retryAfter はRetry-Afterヘッダー（秒数またはHTTP日付）を待ち時間に変換する
+func retryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// drain は再試行する前にレスポンスを読み捨てて接続を再利用できるようにする
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
+// sleep はdだけ待つ。その間にctxが終了した場合はエラーを返す
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to retry MET API request: %w", ctx.Err())
+	}
+}
+
+// cancelBody はボディを閉じたときに試行のコンテキストを解放する
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,148 @@
+package metclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testConfig は待ち時間を短くしたテスト用の設定
+func testConfig() Config {
+	cfg := DefaultConfig()
+	cfg.BaseBackoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	cfg.RateLimit = 0
+	return cfg
+}
+
+// newTestServer はstatusesを順に返し、使い切ったら200を返すサーバー
+func newTestServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			w.Header().Set("Retry-After", r.URL.Query().Get("retryAfter"))
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestClientRetriesRetryableStatuses(t *testing.T) {
+	srv, calls := newTestServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	resp, err := New(testConfig()).Get(context.Background(), srv.URL+"/objects/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("expected 200 after 3 attempts, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	srv, calls := newTestServer(t, http.StatusNotFound)
+	resp, err := New(testConfig()).Get(context.Background(), srv.URL+"/objects/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || calls.Load() != 1 {
+		t.Fatalf("expected a single 404, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClientGivesUpOnLongRetryAfter(t *testing.T) {
+	srv, calls := newTestServer(t, http.StatusTooManyRequests)
+	resp, err := New(testConfig()).Get(context.Background(), srv.URL+"/objects/1?retryAfter=3600")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls.Load() != 1 {
+		t.Fatalf("expected 429 without retry, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClientOpensCircuitAfterConsecutiveFailures(t *testing.T) {
+	srv, calls := newTestServer(t, 500, 500, 500, 500)
+	cfg := testConfig()
+	cfg.MaxAttempts = 2
+	cfg.BreakerThreshold = 3
+	c := New(cfg)
+
+	// 1回目: 2回とも500で、最後のレスポンスが返る
+	resp, err := c.Get(context.Background(), srv.URL+"/objects/1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+
+	// 2回目: 3回連続の失敗でブレーカーが開き、再試行せずに失敗する
+	if _, err := c.Get(context.Background(), srv.URL+"/objects/1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	// 3回目: リクエストを送らずに失敗する
+	if _, err := c.Get(context.Background(), srv.URL+"/objects/1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected requests to stop at the threshold, got %d", calls.Load())
+	}
+}
+
+func TestBreakerHalfOpenProbe(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.record(true)
+	if b.allow() {
+		t.Fatal("expected open breaker to reject")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("expected only one probe while half-open")
+	}
+	b.record(false)
+	if !b.allow() || !b.allow() {
+		t.Fatal("expected closed breaker after a successful probe")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 00:00:10 GMT", 10 * time.Second, true},
+		{"Sun, 31 Dec 2023 23:59:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Package metrics はPrometheus形式で公開するメトリクスを定義する
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry は/metricsで公開するメトリクスのレジストリ
+// テストなどで同じメトリクスを二重に登録しないよう、デフォルトレジストリとは分けている
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestDuration はHTTPリクエストの処理時間（chiのルートパターン・メソッド・ステータスごと）
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by chi route pattern, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// METRequests はMET APIへのリクエスト数（エンドポイント・ステータスごと。通信エラーはstatus="error"）
+	METRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "met_api_requests_total",
+		Help: "Outbound MET API requests by endpoint and status code.",
+	}, []string{"endpoint", "status"})
+
+	// METRequestDuration はMET APIへのリクエストの所要時間
+	METRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "met_api_request_duration_seconds",
+		Help:    "Outbound MET API request latency by endpoint.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"endpoint"})
+
+	// METErrors はMET API呼び出しの失敗数（reason: network, status, decode, circuit_open）
+	METErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "met_api_errors_total",
+		Help: "Failed MET API calls by endpoint and reason (network, status, decode, circuit_open).",
+	}, []string{"endpoint", "reason"})
+
+	// METRetries はMET APIへのリクエストの再試行数（reason: network またはステータスコード）
+	METRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "met_api_retries_total",
+		Help: "Retried MET API requests by endpoint and reason (network or status code).",
+	}, []string{"endpoint", "reason"})
+
+	// METCircuitOpen はMET APIのサーキットブレーカーが開いているか（1: 開いている、0: 閉じている）
+	METCircuitOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "met_api_circuit_open",
+		Help: "Whether the MET API circuit breaker is open (1) or closed (0).",
+	})
+
+	// METCoalesced は実行中の同じ作品の取得に相乗りしてMET APIへのリクエストを省いた数
+	METCoalesced = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "met_api_coalesced_requests_total",
+		Help: "MET object lookups that shared an in-flight request for the same object ID.",
+	})
+
+	// CacheRequests はキャッシュの参照数（result: hit, miss）
+	// ヒット率は rate(cache_requests_total{result="hit"}[5m]) / rate(cache_requests_total[5m]) で求める
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Cache lookups by cache name and result (hit, miss).",
+	}, []string{"cache", "result"})
+
+	// MuseumsCreated は作成したミュージアム数（source: create, fork）
+	MuseumsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "museums_created_total",
+		Help: "Museums created, by source (create, fork).",
+	}, []string{"source"})
+
+	// ArtworksAdded はミュージアムに追加した作品数
+	ArtworksAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "museum_artworks_added_total",
+		Help: "Artworks placed into museums.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		METRequests,
+		METRequestDuration,
+		METErrors,
+		METRetries,
+		METCircuitOpen,
+		METCoalesced,
+		CacheRequests,
+		MuseumsCreated,
+		ArtworksAdded,
+	)
+}
+
+// Handler は/metrics用のハンドラーを返す
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB はsql.DB.Stats()のコネクションプールの状態をgo_sql_*として公開する
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// CacheResult はキャッシュの参照結果を記録する
+func CacheResult(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// METTransport はMET APIへのリクエストのステータス・所要時間・通信エラーを記録するhttp.RoundTripper
+// endpointはURLのパスから求める（/objects/123 -> objects, /search -> search）
+type METTransport struct {
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t METTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	endpoint := METEndpoint(req.URL.Path)
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	METRequestDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		METRequests.WithLabelValues(endpoint, "error").Inc()
+		METErrors.WithLabelValues(endpoint, "network").Inc()
+		return nil, err
+	}
+	METRequests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+	if resp.StatusCode >= http.StatusBadRequest {
+		METErrors.WithLabelValues(endpoint, "status").Inc()
+	}
+	return resp, nil
+}
+
+// METEndpoint はMET APIのURLパスをラベル用のエンドポイント名に変換する
+// 作品IDなどをラベルに含めないよう、/public/collection/v1/ の次の要素だけを使う
+func METEndpoint(path string) string {
+	path = strings.TrimPrefix(path, "/public/collection/v1")
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "root"
+	}
+	endpoint, _, _ := strings.Cut(path, "/")
+	return endpoint
+}
@@ -0,0 +1,118 @@
+// Package render はミュージアムの壁（背景画像と額縁付きの作品）を1枚の画像に合成する
+// フロントエンドのCanvasMergeと同じ配置になるよう、位置は背景に対する%で指定する
+package render
+
+import (
+	"embed"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"sync"
+
+	"golang.org/x/image/draw"
+)
+
+// 合成画像の大きさ（フロントエンドのキャンバス850x550と同じ縦横比）
+const (
+	Width  = 1200
+	Height = 776
+)
+
+// 額縁の見た目（FramedArtworkのborder-8・p-1・border-[#827820]をWidthに合わせて拡大したもの）
+const (
+	frameBorder  = 11
+	framePadding = 6
+	shadowOffset = 6
+)
+
+var (
+	frameColor       = color.RGBA{R: 0x82, G: 0x78, B: 0x20, A: 0xff}
+	matColor         = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	shadowColor      = color.RGBA{A: 0x59} // 不透明度35%の黒
+	placeholderColor = color.RGBA{R: 0xd1, G: 0xd5, B: 0xdb, A: 0xff}
+)
+
+//go:embed backgrounds/*.jpg
+var backgroundFS embed.FS
+
+// Backgrounds は選べる背景の名前（フロントエンドのsrc/assets/backgroundと同じ画像を縮小したもの）
+var Backgrounds = []string{"museum-back-1", "museum-back-2"}
+
+var backgrounds sync.Map // map[string]image.Image
+
+// Background は名前の背景画像を返す（デコード結果は使い回す）
+func Background(name string) (image.Image, error) {
+	if img, ok := backgrounds.Load(name); ok {
+		return img.(image.Image), nil
+	}
+	f, err := backgroundFS.Open("backgrounds/" + name + ".jpg")
+	if err != nil {
+		return nil, fmt.Errorf("unknown background %q", name)
+	}
+	defer f.Close()
+	img, err := jpeg.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode background %q: %w", name, err)
+	}
+	backgrounds.Store(name, img)
+	return img, nil
+}
+
+// Placement は壁に掛ける1作品
+type Placement struct {
+	// Slot は作品を収める枠（背景に対する%、フロントエンドのleft・top・width・heightと同じ）
+	X, Y, W, H int
+	// Artwork は作品画像（nilの場合は画像を取得できなかったものとして無地の額を描く）
+	Artwork image.Image
+}
+
+// SlotRect は枠の位置をピクセルに変換する
+func (p Placement) SlotRect() image.Rectangle {
+	return image.Rect(Width*p.X/100, Height*p.Y/100, Width*(p.X+p.W)/100, Height*(p.Y+p.H)/100)
+}
+
+// InnerSize は額縁と余白を除いた、作品画像を収められる大きさを返す
+func (p Placement) InnerSize() (int, int) {
+	r := p.SlotRect()
+	inset := 2 * (frameBorder + framePadding)
+	return max(1, r.Dx()-inset), max(1, r.Dy()-inset)
+}
+
+// Compose は背景を全面に引き伸ばし、その上に額縁付きの作品を枠の中央に描く
+// 作品は縦横比を保って枠に収め（object-contain）、額縁は作品の大きさに合わせる
+func Compose(bg image.Image, placements []Placement) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), bg, bg.Bounds(), draw.Src, nil)
+
+	for _, p := range placements {
+		slot := p.SlotRect()
+		innerW, innerH := p.InnerSize()
+
+		// 作品を枠に収めた大きさ（画像がなければ枠いっぱい）
+		w, h := innerW, innerH
+		if p.Artwork != nil {
+			b := p.Artwork.Bounds()
+			scale := min(float64(innerW)/float64(b.Dx()), float64(innerH)/float64(b.Dy()))
+			w, h = max(1, int(float64(b.Dx())*scale+0.5)), max(1, int(float64(b.Dy())*scale+0.5))
+		}
+
+		inset := frameBorder + framePadding
+		outerW, outerH := w+2*inset, h+2*inset
+		x0 := slot.Min.X + (slot.Dx()-outerW)/2
+		y0 := slot.Min.Y + (slot.Dy()-outerH)/2
+		outer := image.Rect(x0, y0, x0+outerW, y0+outerH)
+
+		// 影・額縁・余白・作品の順に重ねる
+		draw.Draw(dst, outer.Add(image.Pt(shadowOffset, shadowOffset)), image.NewUniform(shadowColor), image.Point{}, draw.Over)
+		draw.Draw(dst, outer, image.NewUniform(frameColor), image.Point{}, draw.Src)
+		draw.Draw(dst, outer.Inset(frameBorder), image.NewUniform(matColor), image.Point{}, draw.Src)
+		art := outer.Inset(inset)
+		if p.Artwork == nil {
+			draw.Draw(dst, art, image.NewUniform(placeholderColor), image.Point{}, draw.Src)
+			continue
+		}
+		draw.CatmullRom.Scale(dst, art, p.Artwork, p.Artwork.Bounds(), draw.Src, nil)
+	}
+	return dst
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestComposeDrawsFramedArtworkInSlot(t *testing.T) {
+	bgImg := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			bgImg.Set(x, y, color.Black)
+		}
+	}
+	artwork := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			artwork.Set(x, y, color.RGBA{B: 0xff, A: 0xff})
+		}
+	}
+
+	p := Placement{X: 25, Y: 25, W: 50, H: 50, Artwork: artwork}
+	img := Compose(bgImg, []Placement{p})
+	if img.Bounds().Dx() != Width || img.Bounds().Dy() != Height {
+		t.Fatalf("expected %dx%d, got %v", Width, Height, img.Bounds())
+	}
+
+	slot := p.SlotRect()
+	center := image.Pt(slot.Min.X+slot.Dx()/2, slot.Min.Y+slot.Dy()/2)
+	if got := img.RGBAAt(center.X, center.Y); got != (color.RGBA{B: 0xff, A: 0xff}) {
+		t.Fatalf("expected artwork at the slot center, got %v", got)
+	}
+	// 正方形の作品は枠の高さに合わせて収まり、左右は背景のまま
+	if got := img.RGBAAt(slot.Min.X+1, center.Y); got != (color.RGBA{A: 0xff}) {
+		t.Fatalf("expected background beside a contained artwork, got %v", got)
+	}
+	// 額縁は作品を囲む
+	_, innerH := p.InnerSize()
+	frameY := center.Y - innerH/2 - framePadding - frameBorder/2
+	if got := img.RGBAAt(center.X, frameY); got != frameColor {
+		t.Fatalf("expected frame above the artwork, got %v", got)
+	}
+}
+
+func TestBackgrounds(t *testing.T) {
+	for _, name := range Backgrounds {
+		if _, err := Background(name); err != nil {
+			t.Fatalf("background %s: %v", name, err)
+		}
+	}
+	if _, err := Background("../render"); err == nil {
+		t.Fatal("expected an error for an unknown background")
+	}
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ImageCacheRepository は縮小・再エンコード済みの画像を保存するキャッシュ
+// キーは呼び出し側で作るハッシュ値（16進数）で、見つからない場合は(nil, nil)を返す
+type ImageCacheRepository interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// FileImageCache はローカルディスクに画像を保存するImageCacheRepository
+// 1つのディレクトリにファイルが集中しないよう、キーの先頭2文字のサブディレクトリに分けて保存する
+type FileImageCache struct {
+	dir string
+}
+
+// NewFileImageCache はdirを保存先とするFileImageCacheを作成する（dirがなければ作る）
+func NewFileImageCache(dir string) (*FileImageCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create image cache dir: %w", err)
+	}
+	return &FileImageCache{dir: dir}, nil
+}
+
+func (c *FileImageCache) path(key string) (string, error) {
+	if len(key) < 3 || filepath.Base(key) != key {
+		return "", fmt.Errorf("invalid image cache key %q", key)
+	}
+	return filepath.Join(c.dir, key[:2], key), nil
+}
+
+func (c *FileImageCache) Get(ctx context.Context, key string) ([]byte, error) {
+	p, err := c.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Put は一時ファイルに書き込んでからリネームし、読み込み中の途中のファイルを返さないようにする
+func (c *FileImageCache) Put(ctx context.Context, key string, data []byte) error {
+	p, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// PostgresImageCache はimage_cacheテーブルに画像を保存するImageCacheRepository
+// 複数のインスタンスでキャッシュを共有したい場合に使う
+type PostgresImageCache struct {
+	db *sql.DB
+}
+
+func NewPostgresImageCache(db *sql.DB) *PostgresImageCache {
+	return &PostgresImageCache{db: db}
+}
+
+func (c *PostgresImageCache) Get(ctx context.Context, key string) ([]byte, error) {
+	var data []byte
+	err := c.db.QueryRowContext(ctx, `SELECT data FROM image_cache WHERE key = $1`, key).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get image cache: %w", err)
+	}
+	return data, nil
+}
+
+// Put は同じキーがすでにあれば何もしない（同じキーの内容は同じため）
+func (c *PostgresImageCache) Put(ctx context.Context, key string, data []byte) error {
+	_, err := c.db.ExecContext(ctx,
+		`INSERT INTO image_cache (key, data) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING`, key, data)
+	if err != nil {
+		return fmt.Errorf("put image cache: %w", err)
+	}
+	return nil
+}
@@ -5,13 +5,27 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	_ "github.com/jackc/pgx/v5/stdlib"
 
 	"backend/internal/domain"
 )
 
+// ErrAlreadyExists は一意制約に違反した場合に返される
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrVersionConflict は楽観的排他制御で、指定したバージョンが現在のバージョンと異なる場合に返される
+var ErrVersionConflict = errors.New("version conflict")
+
+// isUniqueViolation はPostgreSQLの一意制約違反（23505）か判定する
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 // PostgresItemRepository implements ItemRepository backed by PostgreSQL.
 type PostgresItemRepository struct {
 	db *sql.DB
@@ -73,12 +87,66 @@ func ensureSchema(db *sql.DB) error {
             name VARCHAR(200) NOT NULL,
             description TEXT,
             visibility VARCHAR(10) NOT NULL DEFAULT 'private'
-                CHECK (visibility IN ('public', 'private')),
+                CONSTRAINT museums_visibility_check CHECK (visibility IN ('public', 'private', 'unlisted')),
             image_url VARCHAR(500),
             created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
         );`,
 		`CREATE INDEX IF NOT EXISTS idx_museums_user_id ON museums (user_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_museums_visibility ON museums (visibility);`,
+		// 既存DBの公開設定に限定公開（unlisted）を追加
+		`ALTER TABLE museums DROP CONSTRAINT IF EXISTS museums_visibility_check;`,
+		`ALTER TABLE museums ADD CONSTRAINT museums_visibility_check
+            CHECK (visibility IN ('public', 'private', 'unlisted'));`,
+
+		// 美術館の全文検索（tsvector + 日本語向けのトライグラム）
+		`CREATE EXTENSION IF NOT EXISTS pg_trgm;`,
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS search_vector tsvector
+            GENERATED ALWAYS AS (
+                setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+                setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+            ) STORED;`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_search_vector ON museums USING GIN (search_vector);`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_name_trgm ON museums USING GIN (name gin_trgm_ops);`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_description_trgm ON museums USING GIN (description gin_trgm_ops);`,
+
+		// 美術館のいいね・閲覧数
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS view_count BIGINT NOT NULL DEFAULT 0;`,
+		`CREATE TABLE IF NOT EXISTS museum_likes (
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (museum_id, user_id)
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_likes_user_id ON museum_likes (user_id);`,
+		// 同一訪問者の連続閲覧を間引くための最終閲覧時刻
+		`CREATE TABLE IF NOT EXISTS museum_views (
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            visitor_key VARCHAR(100) NOT NULL,
+            last_viewed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (museum_id, visitor_key)
+        );`,
+
+		// 美術館へのコメント（parent_idで返信をスレッド化）
+		`CREATE TABLE IF NOT EXISTS museum_comments (
+            id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            parent_id BIGINT REFERENCES museum_comments(id) ON DELETE CASCADE,
+            body TEXT NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deleted_at TIMESTAMPTZ
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_comments_museum_roots ON museum_comments (museum_id, created_at DESC) WHERE parent_id IS NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_comments_parent_id ON museum_comments (parent_id);`,
+
+		// 楽観的排他制御用のバージョン（更新ごとに加算し、ETagとして返す）
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;`,
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;`,
+
+		// 美術館の複製（フォーク）元
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS forked_from BIGINT REFERENCES museums(id) ON DELETE SET NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_forked_from ON museums (forked_from);`,
 
 		// 美術館と作品の紐付け
 		`CREATE TABLE IF NOT EXISTS museums_to_arts (
@@ -92,6 +160,24 @@ func ensureSchema(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_museums_to_arts_museum_id ON museums_to_arts (museum_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_museums_to_arts_object_id ON museums_to_arts (object_id);`,
 
+		// 論理削除（ゴミ箱）。deleted_atが入った行は通常のクエリから除外し、保持期間を過ぎたら物理削除する
+		`ALTER TABLE museums ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;`,
+		`ALTER TABLE museums_to_arts ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_deleted_at ON museums (deleted_at) WHERE deleted_at IS NOT NULL;`,
+		`CREATE INDEX IF NOT EXISTS idx_museums_to_arts_deleted_at ON museums_to_arts (deleted_at) WHERE deleted_at IS NOT NULL;`,
+
+		// 美術館の変更履歴（メタデータと展示作品のスナップショット）
+		`CREATE TABLE IF NOT EXISTS museum_revisions (
+            id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            author_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+            action VARCHAR(30) NOT NULL,
+            restored_from BIGINT REFERENCES museum_revisions(id) ON DELETE SET NULL,
+            snapshot JSONB NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_revisions_museum_id ON museum_revisions (museum_id, id DESC);`,
+
 		// ユーザーのお気に入り作品
 		`CREATE TABLE IF NOT EXISTS users_to_arts (
             id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
@@ -103,6 +189,89 @@ func ensureSchema(db *sql.DB) error {
 		`CREATE INDEX IF NOT EXISTS idx_users_to_arts_user_id ON users_to_arts (user_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_users_to_arts_object_id ON users_to_arts (object_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_users_to_arts_created_at ON users_to_arts (created_at);`,
+
+		// 非公開ミュージアムの共有リンク（トークンはハッシュのみ保存）
+		`CREATE TABLE IF NOT EXISTS museum_share_tokens (
+            id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            token_hash CHAR(64) UNIQUE NOT NULL,
+            scope VARCHAR(20) NOT NULL DEFAULT 'view',
+            created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            expires_at TIMESTAMPTZ,
+            revoked_at TIMESTAMPTZ,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_share_tokens_museum_id ON museum_share_tokens (museum_id);`,
+
+		// 共同キュレーター（作成者のmuseums.user_idは含めない）
+		`CREATE TABLE IF NOT EXISTS museum_members (
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (museum_id, user_id)
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_members_user_id ON museum_members (user_id);`,
+		`CREATE TABLE IF NOT EXISTS museum_invitations (
+            id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            invited_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            invitee_user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+            invitee_email VARCHAR(255),
+            role VARCHAR(10) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+            status VARCHAR(10) NOT NULL DEFAULT 'pending'
+                CHECK (status IN ('pending', 'accepted', 'declined', 'revoked')),
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            responded_at TIMESTAMPTZ,
+            CHECK (invitee_user_id IS NOT NULL OR invitee_email IS NOT NULL)
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_invitations_museum_id ON museum_invitations (museum_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_invitations_invitee_user_id ON museum_invitations (invitee_user_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_museum_invitations_invitee_email ON museum_invitations (lower(invitee_email));`,
+
+		// ユーザーのフォロー関係
+		`CREATE TABLE IF NOT EXISTS user_follows (
+            follower_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            followee_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            PRIMARY KEY (follower_id, followee_id),
+            CHECK (follower_id <> followee_id)
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_user_follows_followee_id ON user_follows (followee_id);`,
+
+		// フィード用のアクティビティ
+		`CREATE TABLE IF NOT EXISTS activities (
+            id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+            actor_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+            type VARCHAR(30) NOT NULL,
+            museum_id BIGINT NOT NULL REFERENCES museums(id) ON DELETE CASCADE,
+            object_id BIGINT,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
+		`CREATE INDEX IF NOT EXISTS idx_activities_actor_id ON activities (actor_id, id DESC);`,
+
+		// 部屋の背景と作品の配置（サーバー側の合成画像に使う）
+		`CREATE TABLE IF NOT EXISTS museum_layouts (
+            museum_id BIGINT PRIMARY KEY REFERENCES museums(id) ON DELETE CASCADE,
+            background VARCHAR(50) NOT NULL,
+            placements JSONB NOT NULL,
+            updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
+
+		// 作品画像の代表色（額縁・壁の色の自動選択用）
+		`CREATE TABLE IF NOT EXISTS artwork_palettes (
+            object_id BIGINT PRIMARY KEY,
+            colors JSONB NOT NULL,
+            brightness DOUBLE PRECISION NOT NULL,
+            computed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
+
+		// 縮小・再エンコード済みの作品画像のキャッシュ（IMAGE_CACHE=postgresの場合に使う）
+		`CREATE TABLE IF NOT EXISTS image_cache (
+            key VARCHAR(64) PRIMARY KEY,
+            data BYTEA NOT NULL,
+            created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );`,
 	}
 	for _, s := range stmts {
 		if _, err := db.Exec(s); err != nil {
@@ -112,6 +281,64 @@ func ensureSchema(db *sql.DB) error {
 	return nil
 }
 
+// schemaTables はensureSchemaが作成するテーブル
+var schemaTables = []string{
+	"items", "users", "museums", "museum_likes", "museum_views", "museum_comments",
+	"museums_to_arts", "museum_revisions", "users_to_arts", "museum_share_tokens",
+	"museum_members", "museum_invitations", "user_follows", "activities", "image_cache",
+	"artwork_palettes", "museum_layouts",
+}
+
+// schemaColumns はensureSchemaが既存テーブルに後から追加するカラム（テーブル名.カラム名）
+// 古いスキーマのまま起動していないかの確認に使う
+var schemaColumns = []string{
+	"museums.search_vector", "museums.view_count", "museums.version", "museums.updated_at",
+	"museums.forked_from", "museums.deleted_at", "museums_to_arts.deleted_at",
+}
+
+// CheckSchema はensureSchemaによるマイグレーションがすべて適用済みか確認する
+// 不足しているテーブル・カラムがあればその一覧をエラーで返す
+func CheckSchema(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()`)
+	if err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+	defer rows.Close()
+
+	tables := map[string]bool{}
+	columns := map[string]bool{}
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("read schema: %w", err)
+		}
+		tables[table] = true
+		columns[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read schema: %w", err)
+	}
+
+	var missing []string
+	for _, t := range schemaTables {
+		if !tables[t] {
+			missing = append(missing, t)
+		}
+	}
+	for _, c := range schemaColumns {
+		if !columns[c] {
+			missing = append(missing, c)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("schema not migrated: missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 func (r *PostgresItemRepository) List() ([]domain.Item, error) {
 	rows, err := r.db.Query(`SELECT id, name, created_at FROM items ORDER BY id ASC`)
 	if err != nil {
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain"
+)
+
+// ActivityRepository はフィード用アクティビティのデータアクセス層のインターフェース
+type ActivityRepository interface {
+	Insert(ctx context.Context, a domain.Activity) error
+	ListFeed(ctx context.Context, followerID int, before int, limit int) ([]domain.FeedItem, error)
+}
+
+// PostgresActivityRepository はPostgreSQLを使用したActivityRepositoryの実装
+type PostgresActivityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresActivityRepository は新しいPostgresActivityRepositoryを作成する
+func NewPostgresActivityRepository(db *sql.DB) ActivityRepository {
+	return &PostgresActivityRepository{db: db}
+}
+
+// Insert はアクティビティを記録する
+func (r *PostgresActivityRepository) Insert(ctx context.Context, a domain.Activity) error {
+	query := `
+		INSERT INTO activities (actor_id, type, museum_id, object_id)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	var objectID sql.NullInt64
+	if a.ObjectID != nil {
+		objectID = sql.NullInt64{Int64: int64(*a.ObjectID), Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, a.ActorID, string(a.Type), a.MuseumID, objectID)
+	return err
+}
+
+// ListFeed はfollowerIDのユーザーがフォローしているユーザーのアクティビティを新しい順に取得する
+// beforeが0より大きい場合はそのIDより古いものだけを返す（カーソルページング）
+// 非公開になっているミュージアムのアクティビティは含めない
+func (r *PostgresActivityRepository) ListFeed(ctx context.Context, followerID int, before int, limit int) ([]domain.FeedItem, error) {
+	query := `
+		SELECT a.id, a.type, u.id, u.name, m.id, m.name, a.object_id, a.created_at
+		FROM activities a
+		JOIN user_follows f ON f.followee_id = a.actor_id AND f.follower_id = $1
+		JOIN users u ON u.id = a.actor_id
+		JOIN museums m ON m.id = a.museum_id
+		WHERE m.visibility = 'public' AND m.deleted_at IS NULL
+			AND ($2::bigint = 0 OR a.id < $2::bigint)
+		ORDER BY a.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, followerID, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []domain.FeedItem{}
+	for rows.Next() {
+		var item domain.FeedItem
+		var activityType string
+		var objectID sql.NullInt64
+		if err := rows.Scan(
+			&item.ID,
+			&activityType,
+			&item.Actor.ID,
+			&item.Actor.Name,
+			&item.MuseumID,
+			&item.MuseumName,
+			&objectID,
+			&item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		item.Type = domain.ActivityType(activityType)
+		if objectID.Valid {
+			oid := int(objectID.Int64)
+			item.ObjectID = &oid
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"backend/internal/domain"
+)
+
+// ArtworkPaletteRepository は作品画像の代表色を作品IDごとに保存する
+type ArtworkPaletteRepository interface {
+	FindByObjectIDs(ctx context.Context, objectIDs []int) (map[int]domain.ArtworkPalette, error)
+	Upsert(ctx context.Context, p domain.ArtworkPalette) error
+	// ListMissing は展示中の作品のうち代表色が未計算のものを、excludeを除いて最大limit件返す
+	ListMissing(ctx context.Context, exclude []int, limit int) ([]int, error)
+}
+
+// PostgresArtworkPaletteRepository はPostgreSQLを使用したArtworkPaletteRepositoryの実装
+type PostgresArtworkPaletteRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresArtworkPaletteRepository(db *sql.DB) *PostgresArtworkPaletteRepository {
+	return &PostgresArtworkPaletteRepository{db: db}
+}
+
+func (r *PostgresArtworkPaletteRepository) FindByObjectIDs(ctx context.Context, objectIDs []int) (map[int]domain.ArtworkPalette, error) {
+	palettes := make(map[int]domain.ArtworkPalette, len(objectIDs))
+	if len(objectIDs) == 0 {
+		return palettes, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT object_id, colors, brightness, computed_at
+		FROM artwork_palettes
+		WHERE object_id = ANY($1)
+	`, objectIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.ArtworkPalette
+		var colors []byte
+		if err := rows.Scan(&p.ObjectID, &colors, &p.Brightness, &p.ComputedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(colors, &p.Colors); err != nil {
+			return nil, err
+		}
+		palettes[p.ObjectID] = p
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return palettes, nil
+}
+
+func (r *PostgresArtworkPaletteRepository) Upsert(ctx context.Context, p domain.ArtworkPalette) error {
+	if p.Colors == nil {
+		p.Colors = []domain.PaletteColor{}
+	}
+	colors, err := json.Marshal(p.Colors)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO artwork_palettes (object_id, colors, brightness, computed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (object_id) DO UPDATE
+			SET colors = EXCLUDED.colors, brightness = EXCLUDED.brightness, computed_at = EXCLUDED.computed_at
+	`, p.ObjectID, colors, p.Brightness, p.ComputedAt)
+	return err
+}
+
+func (r *PostgresArtworkPaletteRepository) ListMissing(ctx context.Context, exclude []int, limit int) ([]int, error) {
+	if exclude == nil {
+		exclude = []int{}
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT DISTINCT a.object_id
+		FROM museums_to_arts a
+		LEFT JOIN artwork_palettes p ON p.object_id = a.object_id
+		WHERE a.deleted_at IS NULL AND p.object_id IS NULL AND NOT (a.object_id = ANY($1))
+		ORDER BY a.object_id
+		LIMIT $2
+	`, exclude, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
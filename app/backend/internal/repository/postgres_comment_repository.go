@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain"
+)
+
+// CommentRepository はミュージアムコメントのデータアクセス層のインターフェース
+type CommentRepository interface {
+	Insert(ctx context.Context, c domain.Comment) (*domain.Comment, error)
+	FindByID(ctx context.Context, id int) (*domain.Comment, error)
+	UpdateBody(ctx context.Context, id int, body string) error
+	SoftDelete(ctx context.Context, id int) error
+	ListThreads(ctx context.Context, museumID int, limit, offset int) ([]domain.Comment, int, error)
+}
+
+// PostgresCommentRepository はPostgreSQLを使用したCommentRepositoryの実装
+type PostgresCommentRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresCommentRepository は新しいPostgresCommentRepositoryを作成する
+func NewPostgresCommentRepository(db *sql.DB) CommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+const commentColumns = `id, museum_id, user_id, parent_id, body, created_at, updated_at, deleted_at`
+
+// scanComment は1行分のコメントを読み取る
+func scanComment(row interface{ Scan(dest ...any) error }) (*domain.Comment, error) {
+	var c domain.Comment
+	var parentID sql.NullInt64
+	var deletedAt sql.NullTime
+	if err := row.Scan(
+		&c.ID,
+		&c.MuseumID,
+		&c.UserID,
+		&parentID,
+		&c.Body,
+		&c.CreatedAt,
+		&c.UpdatedAt,
+		&deletedAt,
+	); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		pid := int(parentID.Int64)
+		c.ParentID = &pid
+	}
+	if deletedAt.Valid {
+		c.DeletedAt = &deletedAt.Time
+	}
+	return &c, nil
+}
+
+// Insert は新しいコメントを作成する
+func (r *PostgresCommentRepository) Insert(ctx context.Context, c domain.Comment) (*domain.Comment, error) {
+	query := `
+		INSERT INTO museum_comments (museum_id, user_id, parent_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + commentColumns
+
+	var parentID sql.NullInt64
+	if c.ParentID != nil {
+		parentID = sql.NullInt64{Int64: int64(*c.ParentID), Valid: true}
+	}
+
+	return scanComment(r.db.QueryRowContext(ctx, query, c.MuseumID, c.UserID, parentID, c.Body))
+}
+
+// FindByID は指定IDのコメントを取得する
+func (r *PostgresCommentRepository) FindByID(ctx context.Context, id int) (*domain.Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM museum_comments WHERE id = $1`
+
+	c, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateBody はコメント本文を更新する
+func (r *PostgresCommentRepository) UpdateBody(ctx context.Context, id int, body string) error {
+	query := `
+		UPDATE museum_comments SET body = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, body, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SoftDelete はコメントを削除済みにする
+// 返信のスレッドを保つため行自体は残し、本文を消去する
+func (r *PostgresCommentRepository) SoftDelete(ctx context.Context, id int) error {
+	query := `
+		UPDATE museum_comments SET body = '', deleted_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListThreads はトップレベルのコメントを新しい順にページングして取得し、
+// それぞれの返信（孫以降も含む）を併せて返す。総件数はトップレベルのコメント数
+func (r *PostgresCommentRepository) ListThreads(ctx context.Context, museumID int, limit, offset int) ([]domain.Comment, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM museum_comments WHERE museum_id = $1 AND parent_id IS NULL`
+	if err := r.db.QueryRowContext(ctx, countQuery, museumID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		WITH RECURSIVE roots AS (
+			SELECT id FROM museum_comments
+			WHERE museum_id = $1 AND parent_id IS NULL
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2 OFFSET $3
+		), thread AS (
+			SELECT c.* FROM museum_comments c JOIN roots ON c.id = roots.id
+			UNION ALL
+			SELECT c.* FROM museum_comments c JOIN thread t ON c.parent_id = t.id
+		)
+		SELECT ` + commentColumns + ` FROM thread
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, museumID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	comments := []domain.Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, *c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain"
+)
+
+// FollowRepository はユーザーのフォロー関係のデータアクセス層のインターフェース
+type FollowRepository interface {
+	UserExists(ctx context.Context, userID int) (bool, error)
+	Follow(ctx context.Context, followerID, followeeID int) error
+	Unfollow(ctx context.Context, followerID, followeeID int) error
+	IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error)
+	CountFollowers(ctx context.Context, userID int) (int, error)
+	ListFollowers(ctx context.Context, userID int, limit, offset int) ([]domain.FollowUser, int, error)
+	ListFollowing(ctx context.Context, userID int, limit, offset int) ([]domain.FollowUser, int, error)
+}
+
+// PostgresFollowRepository はPostgreSQLを使用したFollowRepositoryの実装
+type PostgresFollowRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresFollowRepository は新しいPostgresFollowRepositoryを作成する
+func NewPostgresFollowRepository(db *sql.DB) FollowRepository {
+	return &PostgresFollowRepository{db: db}
+}
+
+// UserExists は指定IDのユーザーが存在するか確認する
+func (r *PostgresFollowRepository) UserExists(ctx context.Context, userID int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists)
+	return exists, err
+}
+
+// Follow はフォローする。既にフォロー済みの場合は何もしない
+func (r *PostgresFollowRepository) Follow(ctx context.Context, followerID, followeeID int) error {
+	query := `
+		INSERT INTO user_follows (follower_id, followee_id)
+		VALUES ($1, $2)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+// Unfollow はフォローを解除する。フォローしていない場合は何もしない
+func (r *PostgresFollowRepository) Unfollow(ctx context.Context, followerID, followeeID int) error {
+	query := `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+// IsFollowing はfollowerIDのユーザーがfolloweeIDのユーザーをフォローしているか確認する
+func (r *PostgresFollowRepository) IsFollowing(ctx context.Context, followerID, followeeID int) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM user_follows WHERE follower_id = $1 AND followee_id = $2)`
+
+	var following bool
+	err := r.db.QueryRowContext(ctx, query, followerID, followeeID).Scan(&following)
+	return following, err
+}
+
+// CountFollowers はフォロワー数を取得する
+func (r *PostgresFollowRepository) CountFollowers(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_follows WHERE followee_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// ListFollowers は指定ユーザーのフォロワーを新しい順に取得する
+func (r *PostgresFollowRepository) ListFollowers(ctx context.Context, userID int, limit, offset int) ([]domain.FollowUser, int, error) {
+	return r.list(ctx, userID, "followee_id", "follower_id", limit, offset)
+}
+
+// ListFollowing は指定ユーザーがフォローしているユーザーを新しい順に取得する
+func (r *PostgresFollowRepository) ListFollowing(ctx context.Context, userID int, limit, offset int) ([]domain.FollowUser, int, error) {
+	return r.list(ctx, userID, "follower_id", "followee_id", limit, offset)
+}
+
+// list はフォロー関係の一覧を取得する
+// keyColumnでuserIDを絞り込み、userColumn側のユーザーを返す（カラム名は内部の固定値のみ）
+func (r *PostgresFollowRepository) list(ctx context.Context, userID int, keyColumn, userColumn string, limit, offset int) ([]domain.FollowUser, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM user_follows WHERE ` + keyColumn + ` = $1`
+	if err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.id, u.name, f.created_at
+		FROM user_follows f
+		JOIN users u ON u.id = f.` + userColumn + `
+		WHERE f.` + keyColumn + ` = $1
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []domain.FollowUser{}
+	for rows.Next() {
+		var fu domain.FollowUser
+		if err := rows.Scan(&fu.User.ID, &fu.User.Name, &fu.FollowedAt); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, fu)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
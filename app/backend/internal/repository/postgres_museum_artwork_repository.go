@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain"
+)
+
+// MuseumArtworkRepository はミュージアムに展示する作品（museums_to_arts）のデータアクセス層のインターフェース
+type MuseumArtworkRepository interface {
+	ListByMuseum(ctx context.Context, museumID int) ([]domain.MuseumToArt, error)
+	Insert(ctx context.Context, mta domain.MuseumToArt) (*domain.MuseumToArt, error)
+	SoftDelete(ctx context.Context, museumID, objectID int) error
+	ListDeleted(ctx context.Context, userID int) ([]domain.TrashedArtwork, error)
+	Restore(ctx context.Context, museumID, objectID int) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostgresMuseumArtworkRepository はPostgreSQLを使用したMuseumArtworkRepositoryの実装
+type PostgresMuseumArtworkRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresMuseumArtworkRepository は新しいPostgresMuseumArtworkRepositoryを作成する
+func NewPostgresMuseumArtworkRepository(db *sql.DB) MuseumArtworkRepository {
+	return &PostgresMuseumArtworkRepository{db: db}
+}
+
+// ListByMuseum はミュージアムに展示されている作品を追加順に取得する。ゴミ箱の作品は含まない
+func (r *PostgresMuseumArtworkRepository) ListByMuseum(ctx context.Context, museumID int) ([]domain.MuseumToArt, error) {
+	query := `
+		SELECT id, museum_id, object_id, COALESCE(description, ''), created_at
+		FROM museums_to_arts
+		WHERE museum_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, museumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	artworks := []domain.MuseumToArt{}
+	for rows.Next() {
+		var mta domain.MuseumToArt
+		if err := rows.Scan(&mta.ID, &mta.MuseumID, &mta.ObjectID, &mta.Description, &mta.CreatedAt); err != nil {
+			return nil, err
+		}
+		artworks = append(artworks, mta)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return artworks, nil
+}
+
+// Insert はミュージアムに作品を追加する
+// 同じ作品がゴミ箱にある場合は説明文を置き換えて末尾に戻し、既に展示されている場合はErrAlreadyExistsを返す
+func (r *PostgresMuseumArtworkRepository) Insert(ctx context.Context, mta domain.MuseumToArt) (*domain.MuseumToArt, error) {
+	query := `
+		INSERT INTO museums_to_arts (museum_id, object_id, description)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (museum_id, object_id) DO UPDATE
+			SET description = EXCLUDED.description, created_at = CURRENT_TIMESTAMP, deleted_at = NULL
+			WHERE museums_to_arts.deleted_at IS NOT NULL
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, mta.MuseumID, mta.ObjectID, mta.Description).Scan(&mta.ID, &mta.CreatedAt)
+	if err != nil {
+		// 展示中の行と衝突した場合はDO UPDATEの条件を満たさず、行が返らない
+		if err == sql.ErrNoRows || isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	return &mta, nil
+}
+
+// SoftDelete は展示作品をゴミ箱に移す
+func (r *PostgresMuseumArtworkRepository) SoftDelete(ctx context.Context, museumID, objectID int) error {
+	query := `
+		UPDATE museums_to_arts SET deleted_at = CURRENT_TIMESTAMP
+		WHERE museum_id = $1 AND object_id = $2 AND deleted_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, museumID, objectID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// ListDeleted はユーザーが作成または編集できる（owner・editor役割の）ミュージアムの、ゴミ箱にある展示作品を取得する
+// ミュージアム自体がゴミ箱にある場合は含めない（ミュージアムごと戻す）
+func (r *PostgresMuseumArtworkRepository) ListDeleted(ctx context.Context, userID int) ([]domain.TrashedArtwork, error) {
+	query := `
+		SELECT a.museum_id, m.name, a.object_id, COALESCE(a.description, ''), a.deleted_at
+		FROM museums_to_arts a
+		JOIN museums m ON m.id = a.museum_id
+		WHERE a.deleted_at IS NOT NULL AND m.deleted_at IS NULL
+			AND (
+				m.user_id = $1
+				OR m.id IN (SELECT museum_id FROM museum_members WHERE user_id = $1 AND role IN ('owner', 'editor'))
+			)
+		ORDER BY a.deleted_at DESC, a.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	artworks := []domain.TrashedArtwork{}
+	for rows.Next() {
+		var t domain.TrashedArtwork
+		if err := rows.Scan(&t.MuseumID, &t.MuseumName, &t.ObjectID, &t.Description, &t.DeletedAt); err != nil {
+			return nil, err
+		}
+		artworks = append(artworks, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return artworks, nil
+}
+
+// Restore はゴミ箱の展示作品を元の展示位置に戻す
+func (r *PostgresMuseumArtworkRepository) Restore(ctx context.Context, museumID, objectID int) error {
+	query := `
+		UPDATE museums_to_arts SET deleted_at = NULL
+		WHERE museum_id = $1 AND object_id = $2 AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, museumID, objectID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeDeleted はbeforeより前にゴミ箱に移した展示作品を物理削除し、削除件数を返す
+func (r *PostgresMuseumArtworkRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM museums_to_arts WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
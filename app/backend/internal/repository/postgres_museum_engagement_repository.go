@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"backend/internal/domain"
+)
+
+// MuseumEngagementRepository はミュージアムのいいね・閲覧数のデータアクセス層のインターフェース
+type MuseumEngagementRepository interface {
+	Like(ctx context.Context, museumID, userID int) error
+	Unlike(ctx context.Context, museumID, userID int) error
+	RecordView(ctx context.Context, museumID int, visitorKey string, window time.Duration) (bool, error)
+	GetStats(ctx context.Context, museumIDs []int, callerID int) (map[int]domain.MuseumStats, error)
+}
+
+// PostgresMuseumEngagementRepository はPostgreSQLを使用したMuseumEngagementRepositoryの実装
+type PostgresMuseumEngagementRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresMuseumEngagementRepository は新しいPostgresMuseumEngagementRepositoryを作成する
+func NewPostgresMuseumEngagementRepository(db *sql.DB) MuseumEngagementRepository {
+	return &PostgresMuseumEngagementRepository{db: db}
+}
+
+// Like はミュージアムにいいねを付ける。既にいいね済みの場合は何もしない
+func (r *PostgresMuseumEngagementRepository) Like(ctx context.Context, museumID, userID int) error {
+	query := `
+		INSERT INTO museum_likes (museum_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (museum_id, user_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query, museumID, userID)
+	return err
+}
+
+// Unlike はミュージアムのいいねを取り消す。いいねしていない場合は何もしない
+func (r *PostgresMuseumEngagementRepository) Unlike(ctx context.Context, museumID, userID int) error {
+	query := `DELETE FROM museum_likes WHERE museum_id = $1 AND user_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, museumID, userID)
+	return err
+}
+
+// RecordView は閲覧を記録し、閲覧数を加算する
+// 同じ訪問者がwindow以内に再度閲覧した場合は加算せずfalseを返す
+func (r *PostgresMuseumEngagementRepository) RecordView(ctx context.Context, museumID int, visitorKey string, window time.Duration) (bool, error) {
+	query := `
+		WITH counted AS (
+			INSERT INTO museum_views (museum_id, visitor_key, last_viewed_at)
+			SELECT $1, $2, now()
+			WHERE EXISTS (SELECT 1 FROM museums WHERE id = $1 AND deleted_at IS NULL)
+			ON CONFLICT (museum_id, visitor_key) DO UPDATE
+				SET last_viewed_at = EXCLUDED.last_viewed_at
+				WHERE museum_views.last_viewed_at < now() - ($3::int * interval '1 second')
+			RETURNING museum_id
+		)
+		UPDATE museums SET view_count = view_count + 1
+		WHERE id IN (SELECT museum_id FROM counted)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, museumID, visitorKey, int(window.Seconds()))
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetStats は指定ミュージアムのいいね数・閲覧数・フォーク数と、callerIDのユーザーがいいね済みかを取得する
+func (r *PostgresMuseumEngagementRepository) GetStats(ctx context.Context, museumIDs []int, callerID int) (map[int]domain.MuseumStats, error) {
+	stats := make(map[int]domain.MuseumStats, len(museumIDs))
+	if len(museumIDs) == 0 {
+		return stats, nil
+	}
+
+	query := `
+		SELECT
+			m.id,
+			m.view_count,
+			(SELECT COUNT(*) FROM museum_likes l WHERE l.museum_id = m.id),
+			EXISTS (SELECT 1 FROM museum_likes l WHERE l.museum_id = m.id AND l.user_id = $2),
+			(SELECT COUNT(*) FROM museums f WHERE f.forked_from = m.id AND f.deleted_at IS NULL)
+		FROM museums m
+		WHERE m.id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, museumIDs, callerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var st domain.MuseumStats
+		if err := rows.Scan(&id, &st.ViewCount, &st.LikeCount, &st.LikedByMe, &st.ForkCount); err != nil {
+			return nil, err
+		}
+		stats[id] = st
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"backend/internal/domain"
+)
+
+// MuseumLayoutRepository はミュージアムの部屋の配置を保存する
+type MuseumLayoutRepository interface {
+	FindByMuseumID(ctx context.Context, museumID int) (*domain.MuseumLayout, error)
+	Upsert(ctx context.Context, layout domain.MuseumLayout) (*domain.MuseumLayout, error)
+}
+
+// PostgresMuseumLayoutRepository はPostgreSQLを使用したMuseumLayoutRepositoryの実装
+type PostgresMuseumLayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresMuseumLayoutRepository(db *sql.DB) *PostgresMuseumLayoutRepository {
+	return &PostgresMuseumLayoutRepository{db: db}
+}
+
+func (r *PostgresMuseumLayoutRepository) FindByMuseumID(ctx context.Context, museumID int) (*domain.MuseumLayout, error) {
+	layout := domain.MuseumLayout{MuseumID: museumID}
+	var placements []byte
+	var updatedAt time.Time
+	err := r.db.QueryRowContext(ctx, `
+		SELECT background, placements, updated_at
+		FROM museum_layouts
+		WHERE museum_id = $1
+	`, museumID).Scan(&layout.Background, &placements, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(placements, &layout.Placements); err != nil {
+		return nil, err
+	}
+	layout.UpdatedAt = &updatedAt
+	return &layout, nil
+}
+
+func (r *PostgresMuseumLayoutRepository) Upsert(ctx context.Context, layout domain.MuseumLayout) (*domain.MuseumLayout, error) {
+	if layout.Placements == nil {
+		layout.Placements = []domain.LayoutPlacement{}
+	}
+	placements, err := json.Marshal(layout.Placements)
+	if err != nil {
+		return nil, err
+	}
+	var updatedAt time.Time
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO museum_layouts (museum_id, background, placements)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (museum_id) DO UPDATE
+			SET background = EXCLUDED.background, placements = EXCLUDED.placements, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`, layout.MuseumID, layout.Background, placements).Scan(&updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	layout.IsDefault = false
+	layout.UpdatedAt = &updatedAt
+	return &layout, nil
+}
@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"backend/internal/domain"
+)
+
+// MuseumMemberRepository はミュージアムの共同キュレーターと招待のデータアクセス層のインターフェース
+type MuseumMemberRepository interface {
+	GetRole(ctx context.Context, museumID, userID int) (domain.MuseumRole, error)
+	ListMembers(ctx context.Context, museumID int) ([]domain.MuseumMember, error)
+	UpdateRole(ctx context.Context, museumID, userID int, role domain.MuseumRole) error
+	RemoveMember(ctx context.Context, museumID, userID int) error
+
+	FindUser(ctx context.Context, userID int, email string) (int, string, error)
+	InsertInvitation(ctx context.Context, inv domain.MuseumInvitation) (*domain.MuseumInvitation, error)
+	FindInvitation(ctx context.Context, id int) (*domain.MuseumInvitation, error)
+	ListInvitationsByMuseum(ctx context.Context, museumID int) ([]domain.MuseumInvitation, error)
+	ListInvitationsForUser(ctx context.Context, userID int) ([]domain.MuseumInvitation, error)
+	AcceptInvitation(ctx context.Context, id, userID int) error
+	UpdateInvitationStatus(ctx context.Context, id int, status domain.InvitationStatus) error
+}
+
+// PostgresMuseumMemberRepository はPostgreSQLを使用したMuseumMemberRepositoryの実装
+type PostgresMuseumMemberRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresMuseumMemberRepository は新しいPostgresMuseumMemberRepositoryを作成する
+func NewPostgresMuseumMemberRepository(db *sql.DB) MuseumMemberRepository {
+	return &PostgresMuseumMemberRepository{db: db}
+}
+
+// GetRole はユーザーのミュージアムに対する役割を取得する
+// 作成者はowner、メンバーでなければ空文字を返す
+func (r *PostgresMuseumMemberRepository) GetRole(ctx context.Context, museumID, userID int) (domain.MuseumRole, error) {
+	query := `
+		SELECT CASE WHEN m.user_id = $2 THEN 'owner' ELSE COALESCE(mm.role, '') END
+		FROM museums m
+		LEFT JOIN museum_members mm ON mm.museum_id = m.id AND mm.user_id = $2
+		WHERE m.id = $1
+	`
+
+	var role string
+	if err := r.db.QueryRowContext(ctx, query, museumID, userID).Scan(&role); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return domain.MuseumRole(role), nil
+}
+
+// ListMembers は作成者を先頭に、ミュージアムのメンバーを取得する
+func (r *PostgresMuseumMemberRepository) ListMembers(ctx context.Context, museumID int) ([]domain.MuseumMember, error) {
+	query := `
+		SELECT u.id, u.name, 'owner', TRUE, m.created_at
+		FROM museums m JOIN users u ON u.id = m.user_id
+		WHERE m.id = $1
+		UNION ALL
+		SELECT u.id, u.name, mm.role, FALSE, mm.created_at
+		FROM museum_members mm JOIN users u ON u.id = mm.user_id
+		WHERE mm.museum_id = $1
+		ORDER BY 4 DESC, 5 ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, museumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain.MuseumMember{}
+	for rows.Next() {
+		mem := domain.MuseumMember{MuseumID: museumID}
+		var role string
+		if err := rows.Scan(&mem.User.ID, &mem.User.Name, &role, &mem.IsCreator, &mem.CreatedAt); err != nil {
+			return nil, err
+		}
+		mem.Role = domain.MuseumRole(role)
+		members = append(members, mem)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// UpdateRole はメンバーの役割を変更する
+func (r *PostgresMuseumMemberRepository) UpdateRole(ctx context.Context, museumID, userID int, role domain.MuseumRole) error {
+	query := `UPDATE museum_members SET role = $1 WHERE museum_id = $2 AND user_id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, string(role), museumID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RemoveMember はメンバーを外す
+func (r *PostgresMuseumMemberRepository) RemoveMember(ctx context.Context, museumID, userID int) error {
+	query := `DELETE FROM museum_members WHERE museum_id = $1 AND user_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, museumID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// FindUser はユーザーIDまたはメールアドレスからユーザーを探し、IDとメールアドレスを返す
+// 見つからない場合はIDに0を返す
+func (r *PostgresMuseumMemberRepository) FindUser(ctx context.Context, userID int, email string) (int, string, error) {
+	query := `SELECT id, email FROM users WHERE id = $1 OR ($1 = 0 AND lower(email) = lower($2))`
+
+	var id int
+	var foundEmail string
+	if err := r.db.QueryRowContext(ctx, query, userID, email).Scan(&id, &foundEmail); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", nil
+		}
+		return 0, "", err
+	}
+	return id, foundEmail, nil
+}
+
+const invitationColumns = `
+	i.id, i.museum_id, m.name, i.invited_by, i.invitee_user_id, COALESCE(i.invitee_email, ''),
+	i.role, i.status, i.created_at, i.responded_at
+`
+
+// scanInvitation は1行分の招待を読み取る
+func scanInvitation(row interface{ Scan(dest ...any) error }) (*domain.MuseumInvitation, error) {
+	var inv domain.MuseumInvitation
+	var inviteeUserID sql.NullInt64
+	var role, status string
+	var respondedAt sql.NullTime
+	if err := row.Scan(
+		&inv.ID,
+		&inv.MuseumID,
+		&inv.MuseumName,
+		&inv.InvitedBy,
+		&inviteeUserID,
+		&inv.InviteeEmail,
+		&role,
+		&status,
+		&inv.CreatedAt,
+		&respondedAt,
+	); err != nil {
+		return nil, err
+	}
+	if inviteeUserID.Valid {
+		uid := int(inviteeUserID.Int64)
+		inv.InviteeUserID = &uid
+	}
+	inv.Role = domain.MuseumRole(role)
+	inv.Status = domain.InvitationStatus(status)
+	if respondedAt.Valid {
+		inv.RespondedAt = &respondedAt.Time
+	}
+	return &inv, nil
+}
+
+// InsertInvitation は新しい招待を作成する
+func (r *PostgresMuseumMemberRepository) InsertInvitation(ctx context.Context, inv domain.MuseumInvitation) (*domain.MuseumInvitation, error) {
+	query := `
+		INSERT INTO museum_invitations (museum_id, invited_by, invitee_user_id, invitee_email, role)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING id
+	`
+
+	var inviteeUserID sql.NullInt64
+	if inv.InviteeUserID != nil {
+		inviteeUserID = sql.NullInt64{Int64: int64(*inv.InviteeUserID), Valid: true}
+	}
+
+	var id int
+	if err := r.db.QueryRowContext(ctx, query, inv.MuseumID, inv.InvitedBy, inviteeUserID, inv.InviteeEmail, string(inv.Role)).Scan(&id); err != nil {
+		return nil, err
+	}
+	return r.FindInvitation(ctx, id)
+}
+
+// FindInvitation は指定IDの招待を取得する
+func (r *PostgresMuseumMemberRepository) FindInvitation(ctx context.Context, id int) (*domain.MuseumInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM museum_invitations i JOIN museums m ON m.id = i.museum_id
+		WHERE i.id = $1 AND m.deleted_at IS NULL
+	`
+
+	inv, err := scanInvitation(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return inv, nil
+}
+
+// ListInvitationsByMuseum はミュージアムの保留中の招待を取得する
+func (r *PostgresMuseumMemberRepository) ListInvitationsByMuseum(ctx context.Context, museumID int) ([]domain.MuseumInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM museum_invitations i JOIN museums m ON m.id = i.museum_id
+		WHERE i.museum_id = $1 AND i.status = 'pending'
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+	return r.listInvitations(ctx, query, museumID)
+}
+
+// ListInvitationsForUser はユーザー宛て（ユーザーIDまたは登録メールアドレス宛て）の保留中の招待を取得する
+func (r *PostgresMuseumMemberRepository) ListInvitationsForUser(ctx context.Context, userID int) ([]domain.MuseumInvitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM museum_invitations i JOIN museums m ON m.id = i.museum_id
+		WHERE i.status = 'pending' AND m.deleted_at IS NULL
+			AND (
+				i.invitee_user_id = $1
+				OR lower(i.invitee_email) = (SELECT lower(email) FROM users WHERE id = $1)
+			)
+		ORDER BY i.created_at DESC, i.id DESC
+	`
+	return r.listInvitations(ctx, query, userID)
+}
+
+// listInvitations は招待の一覧を読み取る
+func (r *PostgresMuseumMemberRepository) listInvitations(ctx context.Context, query string, arg int) ([]domain.MuseumInvitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.MuseumInvitation{}
+	for rows.Next() {
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *inv)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitation は招待を承諾し、ユーザーをメンバーに追加する（同一トランザクション）
+// 既にメンバーの場合は招待の役割で上書きする。保留中でない招待はsql.ErrNoRowsを返す
+func (r *PostgresMuseumMemberRepository) AcceptInvitation(ctx context.Context, id, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var museumID int
+	var role string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE museum_invitations
+		SET status = 'accepted', invitee_user_id = $2, responded_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending'
+		RETURNING museum_id, role
+	`, id, userID).Scan(&museumID, &role)
+	if err != nil {
+		return err
+	}
+
+	// 作成者は常にownerなのでメンバーには追加しない
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO museum_members (museum_id, user_id, role)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM museums WHERE id = $1 AND user_id = $2)
+		ON CONFLICT (museum_id, user_id) DO UPDATE SET role = EXCLUDED.role
+	`, museumID, userID, role)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateInvitationStatus は保留中の招待の状態を変更する（辞退・取り消し）
+func (r *PostgresMuseumMemberRepository) UpdateInvitationStatus(ctx context.Context, id int, status domain.InvitationStatus) error {
+	if status == domain.InvitationAccepted {
+		return errors.New("use AcceptInvitation to accept an invitation")
+	}
+
+	query := `
+		UPDATE museum_invitations SET status = $1, responded_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = 'pending'
+	`
+
+	result, err := r.db.ExecContext(ctx, query, string(status), id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
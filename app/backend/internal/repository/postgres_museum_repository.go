@@ -1,17 +1,29 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"strings"
+	"time"
 
 	"backend/internal/domain"
 )
 
 // MuseumRepository はミュージアムのデータアクセス層のインターフェース
 type MuseumRepository interface {
-	GetPublicMuseumsExcludingUser(excludeUserID int, limit int) ([]domain.Museum, error)
-	FindByID(id int) (*domain.Museum, error)
-	UpdateTitle(id int, title string) error
-	Insert(m domain.Museum) (*domain.Museum, error)
+	GetPublicMuseumsExcludingUser(ctx context.Context, excludeUserID int, limit int) ([]domain.Museum, error)
+	FindByID(ctx context.Context, id int) (*domain.Museum, error)
+	UpdateTitle(ctx context.Context, id int, title string, version int) (int, error)
+	UpdateVisibility(ctx context.Context, id int, visibility domain.VisibilityType, version int) (int, error)
+	UpdateImageURL(ctx context.Context, id int, imageURL string, version int) (int, error)
+	Insert(ctx context.Context, m domain.Museum) (*domain.Museum, error)
+	Search(ctx context.Context, query string, callerID int, limit, offset int) ([]domain.Museum, int, error)
+	Fork(ctx context.Context, sourceID, userID int) (*domain.Museum, error)
+	SoftDelete(ctx context.Context, id int) error
+	FindDeleted(ctx context.Context, id int) (*domain.Museum, error)
+	ListDeleted(ctx context.Context, userID int) ([]domain.Museum, error)
+	Restore(ctx context.Context, id int) error
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 }
 
 // PostgresMuseumRepository はPostgreSQLを使用したMuseumRepositoryの実装
@@ -25,16 +37,16 @@ func NewPostgresMuseumRepository(db *sql.DB) MuseumRepository {
 }
 
 // GetPublicMuseumsExcludingUser は指定ユーザー以外の公開ミュージアムを取得する
-func (r *PostgresMuseumRepository) GetPublicMuseumsExcludingUser(excludeUserID int, limit int) ([]domain.Museum, error) {
+func (r *PostgresMuseumRepository) GetPublicMuseumsExcludingUser(ctx context.Context, excludeUserID int, limit int) ([]domain.Museum, error) {
 	query := `
-		SELECT id, user_id, name, description, visibility, image_url, created_at
+		SELECT ` + museumColumns + `
 		FROM museums
-		WHERE visibility='public' AND user_id <> $1
+		WHERE visibility='public' AND user_id <> $1 AND deleted_at IS NULL
 		ORDER BY id DESC
 		LIMIT $2
 	`
 
-	rows, err := r.db.Query(query, excludeUserID, limit)
+	rows, err := r.db.QueryContext(ctx, query, excludeUserID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -42,22 +54,11 @@ func (r *PostgresMuseumRepository) GetPublicMuseumsExcludingUser(excludeUserID i
 
 	var museums []domain.Museum
 	for rows.Next() {
-		var m domain.Museum
-		var visibility string
-		err := rows.Scan(
-			&m.ID,
-			&m.UserID,
-			&m.Name,
-			&m.Description,
-			&visibility,
-			&m.ImageURL,
-			&m.CreatedAt,
-		)
+		m, err := scanMuseum(rows)
 		if err != nil {
 			return nil, err
 		}
-		m.Visibility = domain.VisibilityType(visibility)
-		museums = append(museums, m)
+		museums = append(museums, *m)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -67,17 +68,18 @@ func (r *PostgresMuseumRepository) GetPublicMuseumsExcludingUser(excludeUserID i
 	return museums, nil
 }
 
-// FindByID は指定IDのミュージアムを取得する
-func (r *PostgresMuseumRepository) FindByID(id int) (*domain.Museum, error) {
-	query := `
-		SELECT id, user_id, name, description, visibility, image_url, created_at
-		FROM museums
-		WHERE id = $1
-	`
+// museumColumns はscanMuseumで読み取るSELECT句
+// description・image_urlはNULLを許容するカラムなので空文字に変換して読み取る
+const museumColumns = `id, user_id, name, COALESCE(description, ''), visibility, COALESCE(image_url, ''), created_at, forked_from, deleted_at, version, updated_at`
 
+// scanMuseum は1行分のミュージアムを読み取る
+// SELECT句はmuseumColumnsの順
+func scanMuseum(row interface{ Scan(dest ...any) error }) (*domain.Museum, error) {
 	var m domain.Museum
 	var visibility string
-	err := r.db.QueryRow(query, id).Scan(
+	var forkedFrom sql.NullInt64
+	var deletedAt sql.NullTime
+	if err := row.Scan(
 		&m.ID,
 		&m.UserID,
 		&m.Name,
@@ -85,8 +87,33 @@ func (r *PostgresMuseumRepository) FindByID(id int) (*domain.Museum, error) {
 		&visibility,
 		&m.ImageURL,
 		&m.CreatedAt,
-	)
+		&forkedFrom,
+		&deletedAt,
+		&m.Version,
+		&m.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	m.Visibility = domain.VisibilityType(visibility)
+	if forkedFrom.Valid {
+		id := int(forkedFrom.Int64)
+		m.ForkedFrom = &id
+	}
+	if deletedAt.Valid {
+		m.DeletedAt = &deletedAt.Time
+	}
+	return &m, nil
+}
+
+// FindByID は指定IDのミュージアムを取得する。ゴミ箱のミュージアムは含まない
+func (r *PostgresMuseumRepository) FindByID(ctx context.Context, id int) (*domain.Museum, error) {
+	query := `
+		SELECT ` + museumColumns + `
+		FROM museums
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 
+	m, err := scanMuseum(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -94,15 +121,215 @@ func (r *PostgresMuseumRepository) FindByID(id int) (*domain.Museum, error) {
 		return nil, err
 	}
 
-	m.Visibility = domain.VisibilityType(visibility)
+	return m, nil
+}
+
+// UpdateTitle はミュージアムのタイトルを更新し、更新後のバージョンを返す
+// versionが0より大きい場合は現在のバージョンと一致するときだけ更新し、異なればErrVersionConflictを返す
+func (r *PostgresMuseumRepository) UpdateTitle(ctx context.Context, id int, title string, version int) (int, error) {
+	query := `
+		UPDATE museums SET name = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL AND ($3::bigint = 0 OR version = $3::bigint)
+		RETURNING version
+	`
+
+	return r.updateVersioned(ctx, id, query, title, id, version)
+}
+
+// UpdateVisibility はミュージアムの公開設定を更新し、更新後のバージョンを返す
+// versionの扱いはUpdateTitleと同じ
+func (r *PostgresMuseumRepository) UpdateVisibility(ctx context.Context, id int, visibility domain.VisibilityType, version int) (int, error) {
+	query := `
+		UPDATE museums SET visibility = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL AND ($3::bigint = 0 OR version = $3::bigint)
+		RETURNING version
+	`
+
+	return r.updateVersioned(ctx, id, query, string(visibility), id, version)
+}
+
+// UpdateImageURL はミュージアムのカバー画像のURLを更新し、更新後のバージョンを返す
+// versionの扱いはUpdateTitleと同じ
+func (r *PostgresMuseumRepository) UpdateImageURL(ctx context.Context, id int, imageURL string, version int) (int, error) {
+	query := `
+		UPDATE museums SET image_url = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND deleted_at IS NULL AND ($3::bigint = 0 OR version = $3::bigint)
+		RETURNING version
+	`
+
+	return r.updateVersioned(ctx, id, query, imageURL, id, version)
+}
+
+// updateVersioned はバージョン条件付きのUPDATEを実行する
+// 更新されなかった場合、ミュージアムが存在すればErrVersionConflict、なければsql.ErrNoRowsを返す
+func (r *PostgresMuseumRepository) updateVersioned(ctx context.Context, id int, query string, args ...any) (int, error) {
+	var newVersion int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&newVersion)
+	if err == nil {
+		return newVersion, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM museums WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, ErrVersionConflict
+	}
+	return 0, sql.ErrNoRows
+}
+
+// Insert は新しいミュージアムを作成する
+func (r *PostgresMuseumRepository) Insert(ctx context.Context, m domain.Museum) (*domain.Museum, error) {
+	query := `
+		INSERT INTO museums (user_id, name, description, visibility, image_url)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, version, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, m.UserID, m.Name, m.Description, string(m.Visibility), m.ImageURL).
+		Scan(&m.ID, &m.CreatedAt, &m.Version, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
 	return &m, nil
 }
 
-// UpdateTitle はミュージアムのタイトルを更新する
-func (r *PostgresMuseumRepository) UpdateTitle(id int, title string) error {
-	query := `UPDATE museums SET name = $1 WHERE id = $2`
+// Fork はミュージアムを複製し、userIDのユーザーが所有する非公開ミュージアムとして作成する
+// ミュージアム本体と展示作品（museums_to_arts）・部屋の配置（museum_layouts）を1トランザクションでコピーし、複製元をforked_fromに記録する
+func (r *PostgresMuseumRepository) Fork(ctx context.Context, sourceID, userID int) (*domain.Museum, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var newID int
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO museums (user_id, name, description, visibility, image_url, forked_from)
+		SELECT $2, name, description, 'private', image_url, id
+		FROM museums
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id
+	`, sourceID, userID).Scan(&newID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// 展示順（created_at, id）を保つため元の追加日時ごとコピーする
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO museums_to_arts (museum_id, object_id, description, created_at)
+		SELECT $2, object_id, description, created_at
+		FROM museums_to_arts
+		WHERE museum_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at ASC, id ASC
+	`, sourceID, newID); err != nil {
+		return nil, err
+	}
+
+	// 部屋の配置は作品IDで持っているため、そのままコピーすれば同じ展示になる
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO museum_layouts (museum_id, background, placements)
+		SELECT $2, background, placements
+		FROM museum_layouts
+		WHERE museum_id = $1
+	`, sourceID, newID); err != nil {
+		return nil, err
+	}
+
+	m, err := scanMuseum(tx.QueryRowContext(ctx, `
+		SELECT `+museumColumns+`
+		FROM museums
+		WHERE id = $1
+	`, newID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// museumSearchCondition は検索対象の絞り込み条件
+// $1: 検索語, $2: 呼び出しユーザーID, $3: ILIKE用パターン
+// tsvectorは'simple'設定のため日本語を分かち書きできない。そのためトライグラム（ILIKE / 類似度）で補う
+const museumSearchCondition = `
+		deleted_at IS NULL
+		AND (
+			visibility = 'public'
+			OR user_id = $2
+			OR id IN (SELECT museum_id FROM museum_members WHERE user_id = $2)
+		)
+		AND (
+			search_vector @@ plainto_tsquery('simple', $1)
+			OR name ILIKE $3
+			OR description ILIKE $3
+			OR name % $1
+		)
+`
+
+// Search は名前・説明文でミュージアムを検索し、関連度順に返す
+// 公開ミュージアムと、呼び出しユーザーが作成・参加しているミュージアムのみが対象。総件数も併せて返す
+func (r *PostgresMuseumRepository) Search(ctx context.Context, query string, callerID int, limit, offset int) ([]domain.Museum, int, error) {
+	pattern := "%" + escapeLikePattern(query) + "%"
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM museums WHERE` + museumSearchCondition
+	if err := r.db.QueryRowContext(ctx, countQuery, query, callerID, pattern).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []domain.Museum{}, 0, nil
+	}
+
+	searchQuery := `
+		SELECT ` + museumColumns + `
+		FROM museums
+		WHERE` + museumSearchCondition + `
+		ORDER BY
+			ts_rank(search_vector, plainto_tsquery('simple', $1))
+			+ similarity(name, $1)
+			+ word_similarity($1, COALESCE(description, '')) DESC,
+			id DESC
+		LIMIT $4 OFFSET $5
+	`
 
-	result, err := r.db.Exec(query, title, id)
+	rows, err := r.db.QueryContext(ctx, searchQuery, query, callerID, pattern, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	museums := []domain.Museum{}
+	for rows.Next() {
+		m, err := scanMuseum(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		museums = append(museums, *m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return museums, total, nil
+}
+
+// SoftDelete はミュージアムをゴミ箱に移す（deleted_atを記録する）
+func (r *PostgresMuseumRepository) SoftDelete(ctx context.Context, id int) error {
+	query := `UPDATE museums SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return err
 	}
@@ -119,19 +346,92 @@ func (r *PostgresMuseumRepository) UpdateTitle(id int, title string) error {
 	return nil
 }
 
-// Insert は新しいミュージアムを作成する
-func (r *PostgresMuseumRepository) Insert(m domain.Museum) (*domain.Museum, error) {
+// FindDeleted はゴミ箱にある指定IDのミュージアムを取得する
+func (r *PostgresMuseumRepository) FindDeleted(ctx context.Context, id int) (*domain.Museum, error) {
 	query := `
-		INSERT INTO museums (user_id, name, description, visibility, image_url)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at
+		SELECT ` + museumColumns + `
+		FROM museums
+		WHERE id = $1 AND deleted_at IS NOT NULL
 	`
 
-	err := r.db.QueryRow(query, m.UserID, m.Name, m.Description, string(m.Visibility), m.ImageURL).
-		Scan(&m.ID, &m.CreatedAt)
+	m, err := scanMuseum(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	return &m, nil
+	return m, nil
+}
+
+// ListDeleted はユーザーが作成またはowner役割で参加しているミュージアムのうち、ゴミ箱にあるものを新しく削除した順に取得する
+func (r *PostgresMuseumRepository) ListDeleted(ctx context.Context, userID int) ([]domain.Museum, error) {
+	query := `
+		SELECT ` + museumColumns + `
+		FROM museums
+		WHERE deleted_at IS NOT NULL
+			AND (
+				user_id = $1
+				OR id IN (SELECT museum_id FROM museum_members WHERE user_id = $1 AND role = 'owner')
+			)
+		ORDER BY deleted_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	museums := []domain.Museum{}
+	for rows.Next() {
+		m, err := scanMuseum(rows)
+		if err != nil {
+			return nil, err
+		}
+		museums = append(museums, *m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return museums, nil
+}
+
+// Restore はゴミ箱のミュージアムを元に戻す
+func (r *PostgresMuseumRepository) Restore(ctx context.Context, id int) error {
+	query := `UPDATE museums SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// PurgeDeleted はbeforeより前にゴミ箱に移したミュージアムを物理削除し、削除件数を返す
+// 展示作品・コメントなどの関連行はON DELETE CASCADEでまとめて削除される
+func (r *PostgresMuseumRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM museums WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// escapeLikePattern はLIKEのワイルドカード文字をエスケープする
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"reflect"
+	"testing"
+
+	"backend/internal/domain"
+)
+
+// openTestDB はTEST_DATABASE_URLのPostgreSQLに接続し、スキーマを作成する
+// 未設定の場合はテストをスキップする（テスト用のDBを破壊的に使うため、本番のDSNを指定しないこと）
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := ensureSchema(db); err != nil {
+		t.Fatalf("ensureSchema: %v", err)
+	}
+	return db
+}
+
+func TestForkCopiesLayout(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	var userID int
+	if err := db.QueryRowContext(ctx, `
+		INSERT INTO users (name, email, pass_hash)
+		VALUES ('fork-test', 'fork-test-' || gen_random_uuid() || '@example.com', 'x')
+		RETURNING id
+	`).Scan(&userID); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID) })
+
+	museums := NewPostgresMuseumRepository(db)
+	source, err := museums.Insert(ctx, domain.Museum{UserID: userID, Name: "source", Visibility: domain.VisibilityPublic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	layouts := NewPostgresMuseumLayoutRepository(db)
+	want := []domain.LayoutPlacement{{ObjectID: 436535, X: 10, Y: 20, Width: 30, Height: 40}}
+	if _, err := layouts.Upsert(ctx, domain.MuseumLayout{MuseumID: source.ID, Background: "white", Placements: want}); err != nil {
+		t.Fatal(err)
+	}
+
+	fork, err := museums.Fork(ctx, source.ID, userID)
+	if err != nil || fork == nil {
+		t.Fatalf("Fork() = %v, %v", fork, err)
+	}
+	got, err := layouts.FindByMuseumID(ctx, fork.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil {
+		t.Fatal("fork has no layout")
+	}
+	if got.Background != "white" || !reflect.DeepEqual(got.Placements, want) {
+		t.Errorf("fork layout = %+v, want background white and placements %+v", got, want)
+	}
+}
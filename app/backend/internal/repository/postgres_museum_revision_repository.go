@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"backend/internal/domain"
+)
+
+// MuseumRevisionRepository はミュージアムの変更履歴のデータアクセス層のインターフェース
+type MuseumRevisionRepository interface {
+	Snapshot(ctx context.Context, museumID, authorID int, action domain.RevisionAction) (*domain.MuseumRevision, error)
+	List(ctx context.Context, museumID int, limit, offset int) ([]domain.MuseumRevision, int, error)
+	FindByID(ctx context.Context, id int) (*domain.MuseumRevision, error)
+	Restore(ctx context.Context, museumID, revisionID, authorID int) (*domain.MuseumRevision, error)
+}
+
+// PostgresMuseumRevisionRepository はPostgreSQLを使用したMuseumRevisionRepositoryの実装
+type PostgresMuseumRevisionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresMuseumRevisionRepository は新しいPostgresMuseumRevisionRepositoryを作成する
+func NewPostgresMuseumRevisionRepository(db *sql.DB) MuseumRevisionRepository {
+	return &PostgresMuseumRevisionRepository{db: db}
+}
+
+// queryer は*sql.DBと*sql.Txの共通部分
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// insertSnapshotQuery はミュージアムの現在の状態をスナップショットとして保存する
+// $1: ミュージアムID, $2: 作成者ID（0はNULL）, $3: 操作, $4: 復元元リビジョンID（0はNULL）
+const insertSnapshotQuery = `
+	INSERT INTO museum_revisions (museum_id, author_id, action, restored_from, snapshot)
+	SELECT m.id, NULLIF($2::bigint, 0), $3, NULLIF($4::bigint, 0), jsonb_build_object(
+		'name', m.name,
+		'description', COALESCE(m.description, ''),
+		'visibility', m.visibility,
+		'imageUrl', COALESCE(m.image_url, ''),
+		'artworks', COALESCE((
+			SELECT jsonb_agg(jsonb_build_object(
+				'objectId', a.object_id,
+				'description', COALESCE(a.description, '')
+			) ORDER BY a.created_at, a.id)
+			FROM museums_to_arts a
+			WHERE a.museum_id = m.id AND a.deleted_at IS NULL
+		), '[]'::jsonb),
+		'layout', (
+			SELECT jsonb_build_object('background', l.background, 'placements', l.placements)
+			FROM museum_layouts l
+			WHERE l.museum_id = m.id
+		)
+	)
+	FROM museums m
+	WHERE m.id = $1
+	RETURNING id
+`
+
+// selectRevisionQuery はリビジョンを作成者名付きで取得する
+const selectRevisionQuery = `
+	SELECT r.id, r.museum_id, u.id, u.name, r.action, r.restored_from, r.snapshot, r.created_at
+	FROM museum_revisions r
+	LEFT JOIN users u ON u.id = r.author_id
+`
+
+// scanRevision は1行分のリビジョンを読み取る
+func scanRevision(row interface{ Scan(dest ...any) error }) (*domain.MuseumRevision, error) {
+	var rev domain.MuseumRevision
+	var authorID sql.NullInt64
+	var authorName sql.NullString
+	var action string
+	var restoredFrom sql.NullInt64
+	var snapshot []byte
+	if err := row.Scan(&rev.ID, &rev.MuseumID, &authorID, &authorName, &action, &restoredFrom, &snapshot, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(snapshot, &rev.Snapshot); err != nil {
+		return nil, err
+	}
+	rev.Action = domain.RevisionAction(action)
+	if authorID.Valid {
+		rev.Author = &domain.UserSummary{ID: int(authorID.Int64), Name: authorName.String}
+	}
+	if restoredFrom.Valid {
+		id := int(restoredFrom.Int64)
+		rev.RestoredFrom = &id
+	}
+	return &rev, nil
+}
+
+// Snapshot はミュージアムの現在の状態をリビジョンとして記録する
+// ミュージアムが存在しない場合はnilを返す
+func (r *PostgresMuseumRevisionRepository) Snapshot(ctx context.Context, museumID, authorID int, action domain.RevisionAction) (*domain.MuseumRevision, error) {
+	return insertSnapshot(ctx, r.db, museumID, authorID, action, 0)
+}
+
+// insertSnapshot はスナップショットを保存し、保存したリビジョンを返す
+func insertSnapshot(ctx context.Context, q queryer, museumID, authorID int, action domain.RevisionAction, restoredFrom int) (*domain.MuseumRevision, error) {
+	var id int
+	if err := q.QueryRowContext(ctx, insertSnapshotQuery, museumID, authorID, string(action), restoredFrom).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return scanRevision(q.QueryRowContext(ctx, selectRevisionQuery+` WHERE r.id = $1`, id))
+}
+
+// List はミュージアムのリビジョンを新しい順に取得する。総件数も併せて返す
+func (r *PostgresMuseumRevisionRepository) List(ctx context.Context, museumID int, limit, offset int) ([]domain.MuseumRevision, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM museum_revisions WHERE museum_id = $1`, museumID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectRevisionQuery+`
+		WHERE r.museum_id = $1
+		ORDER BY r.id DESC
+		LIMIT $2 OFFSET $3
+	`, museumID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	revisions := []domain.MuseumRevision{}
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		revisions = append(revisions, *rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// FindByID は指定IDのリビジョンを取得する
+func (r *PostgresMuseumRevisionRepository) FindByID(ctx context.Context, id int) (*domain.MuseumRevision, error) {
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, selectRevisionQuery+` WHERE r.id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rev, nil
+}
+
+// Restore はリビジョンのスナップショットでミュージアムの名前・説明文・画像・展示作品と部屋の配置を置き換え、
+// 復元後の状態を新しいリビジョンとして記録する。すべて1トランザクションで行う
+// 公開設定はアクセス制御のため復元しない。配置のないスナップショットでは現在の配置をそのまま残す
+func (r *PostgresMuseumRevisionRepository) Restore(ctx context.Context, museumID, revisionID, authorID int) (*domain.MuseumRevision, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	source, err := scanRevision(tx.QueryRowContext(ctx, selectRevisionQuery+` WHERE r.id = $1 AND r.museum_id = $2`, revisionID, museumID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	snap := source.Snapshot
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE museums SET name = $1, description = $2, image_url = $3,
+			version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL
+	`, snap.Name, snap.Description, snap.ImageURL, museumID)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, nil
+	}
+
+	// スナップショットにない展示作品はゴミ箱に移す
+	objectIDs := make([]int64, len(snap.Artworks))
+	for i, art := range snap.Artworks {
+		objectIDs[i] = int64(art.ObjectID)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE museums_to_arts SET deleted_at = CURRENT_TIMESTAMP
+		WHERE museum_id = $1 AND deleted_at IS NULL AND NOT (object_id = ANY($2))
+	`, museumID, objectIDs); err != nil {
+		return nil, err
+	}
+	// スナップショットの作品を展示順に並べ直す（ゴミ箱にある作品は戻す）
+	// 展示順はcreated_atで決まるため、文ごとに進むclock_timestamp()を使う
+	for _, art := range snap.Artworks {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO museums_to_arts (museum_id, object_id, description, created_at)
+			VALUES ($1, $2, $3, clock_timestamp())
+			ON CONFLICT (museum_id, object_id) DO UPDATE
+				SET description = EXCLUDED.description, created_at = EXCLUDED.created_at, deleted_at = NULL
+		`, museumID, art.ObjectID, art.Description); err != nil {
+			return nil, err
+		}
+	}
+
+	if snap.Layout != nil {
+		placements, err := json.Marshal(snap.Layout.Placements)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO museum_layouts (museum_id, background, placements)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (museum_id) DO UPDATE
+				SET background = EXCLUDED.background, placements = EXCLUDED.placements, updated_at = CURRENT_TIMESTAMP
+		`, museumID, snap.Layout.Background, placements); err != nil {
+			return nil, err
+		}
+	}
+
+	rev, err := insertSnapshot(ctx, tx, museumID, authorID, domain.RevisionRestore, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"backend/internal/domain"
+)
+
+// ShareTokenRepository はミュージアム共有リンクのデータアクセス層のインターフェース
+type ShareTokenRepository interface {
+	Insert(ctx context.Context, t domain.ShareToken) (*domain.ShareToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*domain.ShareToken, error)
+	ListByMuseum(ctx context.Context, museumID int) ([]domain.ShareToken, error)
+	Revoke(ctx context.Context, id int, museumID int) error
+}
+
+// PostgresShareTokenRepository はPostgreSQLを使用したShareTokenRepositoryの実装
+type PostgresShareTokenRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresShareTokenRepository は新しいPostgresShareTokenRepositoryを作成する
+func NewPostgresShareTokenRepository(db *sql.DB) ShareTokenRepository {
+	return &PostgresShareTokenRepository{db: db}
+}
+
+const shareTokenColumns = `id, museum_id, token_hash, scope, created_by, expires_at, revoked_at, created_at`
+
+// scanShareToken は1行分の共有リンクを読み取る
+func scanShareToken(row interface{ Scan(dest ...any) error }) (*domain.ShareToken, error) {
+	var t domain.ShareToken
+	var scope string
+	var expiresAt, revokedAt sql.NullTime
+	if err := row.Scan(
+		&t.ID,
+		&t.MuseumID,
+		&t.TokenHash,
+		&scope,
+		&t.CreatedBy,
+		&expiresAt,
+		&revokedAt,
+		&t.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	t.Scope = domain.ShareScope(scope)
+	if expiresAt.Valid {
+		t.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		t.RevokedAt = &revokedAt.Time
+	}
+	return &t, nil
+}
+
+// Insert は新しい共有リンクを作成する
+func (r *PostgresShareTokenRepository) Insert(ctx context.Context, t domain.ShareToken) (*domain.ShareToken, error) {
+	query := `
+		INSERT INTO museum_share_tokens (museum_id, token_hash, scope, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + shareTokenColumns
+
+	var expiresAt sql.NullTime
+	if t.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *t.ExpiresAt, Valid: true}
+	}
+
+	return scanShareToken(r.db.QueryRowContext(ctx, query, t.MuseumID, t.TokenHash, string(t.Scope), t.CreatedBy, expiresAt))
+}
+
+// FindByHash はトークンのハッシュから共有リンクを取得する
+func (r *PostgresShareTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.ShareToken, error) {
+	query := `SELECT ` + shareTokenColumns + ` FROM museum_share_tokens WHERE token_hash = $1`
+
+	t, err := scanShareToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// ListByMuseum はミュージアムの共有リンクを新しい順に取得する
+func (r *PostgresShareTokenRepository) ListByMuseum(ctx context.Context, museumID int) ([]domain.ShareToken, error) {
+	query := `
+		SELECT ` + shareTokenColumns + `
+		FROM museum_share_tokens
+		WHERE museum_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, museumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.ShareToken{}
+	for rows.Next() {
+		t, err := scanShareToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke は共有リンクを無効化する。既に無効化済みの場合も成功とする
+func (r *PostgresShareTokenRepository) Revoke(ctx context.Context, id int, museumID int) error {
+	query := `
+		UPDATE museum_share_tokens SET revoked_at = COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id = $1 AND museum_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, museumID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
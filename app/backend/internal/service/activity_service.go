@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// ActivityRecorder はフィードに載せるアクティビティを記録する
+// 記録の失敗で元の操作（ミュージアム作成など）を失敗させないよう、エラーは返さない
+type ActivityRecorder interface {
+	Record(ctx context.Context, a domain.Activity)
+}
+
+// ActivityService はアクティビティの記録とフィードのビジネスロジックを含む
+type ActivityService struct {
+	repo repository.ActivityRepository
+	log  *slog.Logger
+}
+
+// NewActivityService は新しいActivityServiceを作成する
+func NewActivityService(repo repository.ActivityRepository, log *slog.Logger) *ActivityService {
+	return &ActivityService{repo: repo, log: log}
+}
+
+// Record はアクティビティを記録する。失敗した場合はログに残すのみ
+func (s *ActivityService) Record(ctx context.Context, a domain.Activity) {
+	if err := s.repo.Insert(ctx, a); err != nil {
+		s.log.Error("failed to record activity",
+			slog.String("error", err.Error()),
+			slog.String("type", string(a.Type)),
+			slog.Int("actorId", a.ActorID),
+			slog.Int("museumId", a.MuseumID),
+		)
+	}
+}
+
+// GetFeed はフォローしているユーザーの最近のアクティビティを取得する
+// beforeには前回レスポンスのnextCursorを指定する（0で先頭から）
+func (s *ActivityService) GetFeed(ctx context.Context, userID int, before int, limit int) (*domain.FeedResponse, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20 // デフォルト値
+	}
+	if before < 0 {
+		before = 0
+	}
+
+	items, err := s.repo.ListFeed(ctx, userID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	res := &domain.FeedResponse{Items: items}
+	if len(items) == limit {
+		next := items[len(items)-1].ID
+		res.NextCursor = &next
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// pagedActivityRepo はID 1〜total のアクティビティを新しい順に返すActivityRepository
+type pagedActivityRepo struct {
+	repository.ActivityRepository
+	total     int
+	lastLimit int
+}
+
+func (r *pagedActivityRepo) ListFeed(_ context.Context, _ int, before int, limit int) ([]domain.FeedItem, error) {
+	r.lastLimit = limit
+	items := []domain.FeedItem{}
+	id := r.total
+	if before > 0 && before-1 < id {
+		id = before - 1
+	}
+	for ; id > 0 && len(items) < limit; id-- {
+		items = append(items, domain.FeedItem{ID: id})
+	}
+	return items, nil
+}
+
+func TestGetFeedNextCursor(t *testing.T) {
+	s := NewActivityService(&pagedActivityRepo{total: 5}, slog.Default())
+
+	// 1ページ目は件数ちょうどなので続きのカーソルを返す
+	first, err := s.GetFeed(context.Background(), 1, 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Items) != 3 || first.NextCursor == nil || *first.NextCursor != 3 {
+		t.Fatalf("first page: got %d items, cursor %v", len(first.Items), first.NextCursor)
+	}
+
+	// 2ページ目は件数に満たないので最後のページ
+	second, err := s.GetFeed(context.Background(), 1, *first.NextCursor, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Items) != 2 || second.NextCursor != nil {
+		t.Errorf("second page: got %d items, cursor %v", len(second.Items), second.NextCursor)
+	}
+}
+
+func TestGetFeedClampsLimit(t *testing.T) {
+	repo := &pagedActivityRepo{}
+	s := NewActivityService(repo, slog.Default())
+	for _, tc := range []struct{ limit, want int }{{0, 20}, {-1, 20}, {101, 20}, {100, 100}, {5, 5}} {
+		if _, err := s.GetFeed(context.Background(), 1, 0, tc.limit); err != nil {
+			t.Fatal(err)
+		}
+		if repo.lastLimit != tc.want {
+			t.Errorf("limit %d: repository got %d, want %d", tc.limit, repo.lastLimit, tc.want)
+		}
+	}
+}
+
+func TestGetFeedRequiresUser(t *testing.T) {
+	s := NewActivityService(&pagedActivityRepo{}, slog.Default())
+	if _, err := s.GetFeed(context.Background(), 0, 0, 20); err == nil || err.Error() != "invalid user ID" {
+		t.Errorf("expected invalid user ID, got %v", err)
+	}
+}
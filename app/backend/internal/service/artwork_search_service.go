@@ -1,29 +1,35 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
-	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"backend/internal/domain"
+	"backend/internal/metclient"
+	"backend/internal/metrics"
+	"backend/internal/tracing"
 )
 
 // ArtworkSearchService はMET APIを使用した作品検索サービス
 type ArtworkSearchService struct {
-	client  *http.Client
-	baseURL string
+	client   *metclient.Client
+	baseURL  string
+	palettes PaletteLookup // nilの場合はexpand=paletteを無視する
 }
 
-// NewArtworkSearchService は新しいArtworkSearchServiceを作成する
-func NewArtworkSearchService() *ArtworkSearchService {
+// NewArtworkSearchService はMET APIの共有クライアントを使うArtworkSearchServiceを作成する
+func NewArtworkSearchService(client *metclient.Client, palettes PaletteLookup) *ArtworkSearchService {
 	return &ArtworkSearchService{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		baseURL: "https://collectionapi.metmuseum.org/public/collection/v1",
+		client:   client,
+		baseURL:  "https://collectionapi.metmuseum.org/public/collection/v1",
+		palettes: palettes,
 	}
 }
 
@@ -31,10 +37,15 @@ func NewArtworkSearchService() *ArtworkSearchService {
 type MetSearchResponse struct {
 	Total     int   `json:"total"`
 	ObjectIDs []int `json:"objectIDs"`
+	// Palettes はexpand=paletteの場合に、objectIDsのうち代表色が計算済みの作品を同じ順で返す
+	Palettes []domain.ArtworkPalette `json:"palettes,omitempty"`
 }
 
 // SearchArtworks はMET APIを使用して作品を検索する
-func (s *ArtworkSearchService) SearchArtworks(query domain.ArtworkSearchQuery, limit int) (*MetSearchResponse, error) {
+func (s *ArtworkSearchService) SearchArtworks(ctx context.Context, query domain.ArtworkSearchQuery, limit int) (result *MetSearchResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ArtworkSearchService.SearchArtworks", attribute.Int("met.limit", limit))
+	defer func() { tracing.End(span, err) }()
+
 	if limit <= 0 || limit > 100 {
 		limit = 20 // デフォルト値
 	}
@@ -60,7 +71,7 @@ func (s *ArtworkSearchService) SearchArtworks(query domain.ArtworkSearchQuery, l
 	// APIリクエストを実行
 	searchURL := fmt.Sprintf("%s/search?%s", s.baseURL, params.Encode())
 	
-	resp, err := s.client.Get(searchURL)
+	resp, err := s.client.Get(ctx, searchURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call MET API: %w", err)
 	}
@@ -72,13 +83,26 @@ func (s *ArtworkSearchService) SearchArtworks(query domain.ArtworkSearchQuery, l
 
 	var searchResp MetSearchResponse
 	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		metrics.METErrors.WithLabelValues("search", "decode").Inc()
 		return nil, fmt.Errorf("failed to decode MET API response: %w", err)
 	}
 
+	span.SetAttributes(attribute.Int("met.total", searchResp.Total))
+
 	// 結果を制限
 	if len(searchResp.ObjectIDs) > limit {
 		searchResp.ObjectIDs = searchResp.ObjectIDs[:limit]
 	}
 
+	if s.palettes != nil && slices.Contains(query.Expand, "palette") {
+		found := s.palettes.Lookup(ctx, searchResp.ObjectIDs)
+		searchResp.Palettes = []domain.ArtworkPalette{}
+		for _, id := range searchResp.ObjectIDs {
+			if p, ok := found[id]; ok {
+				searchResp.Palettes = append(searchResp.Palettes, p)
+			}
+		}
+	}
+
 	return &searchResp, nil
 }
\ No newline at end of file
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"backend/internal/domain"
+	"backend/internal/metrics"
+	"backend/internal/repository"
+)
+
+// ArtworkService はミュージアムに展示する作品のビジネスロジックを含む
+type ArtworkService struct {
+	repo       repository.MuseumArtworkRepository
+	museumRepo repository.MuseumRepository
+	access     *MuseumAccess
+	activity   ActivityRecorder
+	revisions  RevisionRecorder
+	palettes   PaletteLookup
+}
+
+// NewArtworkService は新しいArtworkServiceを作成する
+func NewArtworkService(repo repository.MuseumArtworkRepository, museumRepo repository.MuseumRepository, access *MuseumAccess, activity ActivityRecorder, revisions RevisionRecorder, palettes PaletteLookup) *ArtworkService {
+	return &ArtworkService{repo: repo, museumRepo: museumRepo, access: access, activity: activity, revisions: revisions, palettes: palettes}
+}
+
+// ListArtworks はミュージアムに展示されている作品を取得する
+// 閲覧権限のない非公開ミュージアムは存在しないものとして扱う
+func (s *ArtworkService) ListArtworks(ctx context.Context, museumID int, callerID int) ([]domain.ArtworkInMuseum, error) {
+	return s.listArtworks(ctx, museumID, callerID, false)
+}
+
+// ListSharedArtworks は共有リンク経由でミュージアムの展示作品を取得する
+// 共有リンクの検証は呼び出し側（ShareService）で済んでいる前提で、公開設定を問わず返す
+func (s *ArtworkService) ListSharedArtworks(ctx context.Context, museumID int) ([]domain.ArtworkInMuseum, error) {
+	return s.listArtworks(ctx, museumID, 0, true)
+}
+
+// listArtworks は展示作品を取得する
+func (s *ArtworkService) listArtworks(ctx context.Context, museumID int, callerID int, shared bool) ([]domain.ArtworkInMuseum, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+
+	if shared {
+		museum, err := s.museumRepo.FindByID(ctx, museumID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get museum: %w", err)
+		}
+		if museum == nil {
+			return nil, errors.New("museum not found")
+		}
+	} else if _, err := s.access.Authorize(ctx, museumID, callerID, domain.PermissionView); err != nil {
+		return nil, err
+	}
+
+	placements, err := s.repo.ListByMuseum(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artworks: %w", err)
+	}
+
+	artworks := make([]domain.ArtworkInMuseum, len(placements))
+	for i, p := range placements {
+		artworks[i] = p.ToArtworkInMuseum()
+	}
+	s.attachPalettes(ctx, artworks)
+	return artworks, nil
+}
+
+// attachPalettes は計算済みの作品に代表色を付け加える
+func (s *ArtworkService) attachPalettes(ctx context.Context, artworks []domain.ArtworkInMuseum) {
+	if s.palettes == nil || len(artworks) == 0 {
+		return
+	}
+	ids := make([]int, len(artworks))
+	for i, a := range artworks {
+		ids[i] = a.ObjectID
+	}
+	palettes := s.palettes.Lookup(ctx, ids)
+	for i := range artworks {
+		if p, ok := palettes[artworks[i].ObjectID]; ok {
+			artworks[i].Palette = &p
+		}
+	}
+}
+
+// AddArtwork はミュージアムに作品を追加する。追加できるのは所有者と編集者
+func (s *ArtworkService) AddArtwork(ctx context.Context, museumID int, userID int, req domain.MuseumToArtCreateRequest) (*domain.MuseumToArtResponse, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if req.ObjectID <= 0 {
+		return nil, errors.New("invalid object ID")
+	}
+
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Insert(ctx, domain.MuseumToArt{
+		MuseumID:    museumID,
+		ObjectID:    req.ObjectID,
+		Description: strings.TrimSpace(req.Description),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExists) {
+			return nil, errors.New("artwork already in museum")
+		}
+		return nil, fmt.Errorf("failed to add artwork: %w", err)
+	}
+
+	metrics.ArtworksAdded.Inc()
+	if s.activity != nil {
+		objectID := created.ObjectID
+		s.activity.Record(ctx, domain.Activity{
+			ActorID:  userID,
+			Type:     domain.ActivityArtworkAdded,
+			MuseumID: museumID,
+			ObjectID: &objectID,
+		})
+	}
+
+	s.recordRevision(ctx, museumID, userID, domain.RevisionAddArtwork)
+
+	response := created.ToResponse()
+	return &response, nil
+}
+
+// RemoveArtwork は展示作品をゴミ箱に移す。外せるのは所有者と編集者
+func (s *ArtworkService) RemoveArtwork(ctx context.Context, museumID int, objectID int, userID int) error {
+	if museumID <= 0 {
+		return errors.New("invalid museum ID")
+	}
+	if userID <= 0 {
+		return errors.New("invalid user ID")
+	}
+	if objectID <= 0 {
+		return errors.New("invalid object ID")
+	}
+
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, museumID, objectID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("artwork not found")
+		}
+		return fmt.Errorf("failed to remove artwork: %w", err)
+	}
+
+	s.recordRevision(ctx, museumID, userID, domain.RevisionRemoveArtwork)
+	return nil
+}
+
+// recordRevision は変更後のミュージアムをリビジョンとして記録する
+func (s *ArtworkService) recordRevision(ctx context.Context, museumID int, authorID int, action domain.RevisionAction) {
+	if s.revisions == nil {
+		return
+	}
+	s.revisions.Record(ctx, museumID, authorID, action)
+}
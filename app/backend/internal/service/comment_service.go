@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// maxCommentLength はコメント本文の最大文字数
+const maxCommentLength = 1000
+
+// CommentService はミュージアムコメントのビジネスロジックを含む
+type CommentService struct {
+	repo      repository.CommentRepository
+	access    *MuseumAccess
+	moderator CommentModerator
+}
+
+// NewCommentService は新しいCommentServiceを作成する
+func NewCommentService(repo repository.CommentRepository, access *MuseumAccess, moderator CommentModerator) *CommentService {
+	return &CommentService{repo: repo, access: access, moderator: moderator}
+}
+
+// ListComments はミュージアムのコメントをスレッド単位でページングして取得する
+func (s *CommentService) ListComments(ctx context.Context, museumID int, callerID int, limit, offset int) (*domain.CommentPageResponse, error) {
+	if _, err := s.visibleMuseum(ctx, museumID, callerID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20 // デフォルト値
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	comments, total, err := s.repo.ListThreads(ctx, museumID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	return &domain.CommentPageResponse{
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+		Comments: buildCommentThreads(comments),
+	}, nil
+}
+
+// CreateComment はコメントまたは返信を投稿する
+func (s *CommentService) CreateComment(ctx context.Context, museumID int, userID int, req domain.CommentCreateRequest) (*domain.CommentResponse, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if _, err := s.visibleMuseum(ctx, museumID, userID); err != nil {
+		return nil, err
+	}
+
+	body, err := s.screenBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.repo.FindByID(ctx, *req.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent comment: %w", err)
+		}
+		if parent == nil || parent.MuseumID != museumID || parent.IsDeleted() {
+			return nil, errors.New("parent comment not found")
+		}
+	}
+
+	created, err := s.repo.Insert(ctx, domain.Comment{
+		MuseumID: museumID,
+		UserID:   userID,
+		ParentID: req.ParentID,
+		Body:     body,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	response := created.ToResponse()
+	return &response, nil
+}
+
+// UpdateComment はコメント本文を編集する。編集できるのは投稿者のみ
+func (s *CommentService) UpdateComment(ctx context.Context, id int, userID int, req domain.CommentUpdateRequest) (*domain.CommentResponse, error) {
+	comment, err := s.findActiveComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, errors.New("permission denied")
+	}
+
+	body, err := s.screenBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateBody(ctx, id, body); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("comment not found")
+		}
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	updated, err := s.findActiveComment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	response := updated.ToResponse()
+	return &response, nil
+}
+
+// DeleteComment はコメントを削除する。削除できるのは投稿者とミュージアムのowner役割のユーザー
+func (s *CommentService) DeleteComment(ctx context.Context, id int, userID int) error {
+	comment, err := s.findActiveComment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID {
+		if _, err := s.access.Authorize(ctx, comment.MuseumID, userID, domain.PermissionManage); err != nil {
+			if err.Error() == "museum not found" {
+				return errors.New("comment not found")
+			}
+			return err
+		}
+	}
+
+	if err := s.repo.SoftDelete(ctx, id); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("comment not found")
+		}
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// visibleMuseum は呼び出しユーザーが閲覧できるミュージアムを取得する
+// 閲覧権限のない非公開ミュージアムは存在しないものとして扱う
+func (s *CommentService) visibleMuseum(ctx context.Context, museumID int, callerID int) (*domain.Museum, error) {
+	return s.access.Authorize(ctx, museumID, callerID, domain.PermissionView)
+}
+
+// findActiveComment は削除されていないコメントを取得する
+func (s *CommentService) findActiveComment(ctx context.Context, id int) (*domain.Comment, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid comment ID")
+	}
+
+	comment, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment == nil || comment.IsDeleted() {
+		return nil, errors.New("comment not found")
+	}
+	return comment, nil
+}
+
+// screenBody は本文を検証し、モデレーションフィルターに通す
+func (s *CommentService) screenBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", errors.New("comment body is too long (max 1000)")
+	}
+
+	result, err := s.moderator.Moderate(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to moderate comment: %w", err)
+	}
+	if !result.Allowed {
+		return "", errors.New("comment rejected by moderation")
+	}
+	return body, nil
+}
+
+// buildCommentThreads は作成日時順に並んだコメントを返信のツリーに組み立てる
+// トップレベルのコメントは新しい順、返信は古い順に並べる
+func buildCommentThreads(comments []domain.Comment) []domain.CommentResponse {
+	children := make(map[int][]domain.Comment)
+	var roots []domain.Comment
+	for _, c := range comments {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	var build func(c domain.Comment) domain.CommentResponse
+	build = func(c domain.Comment) domain.CommentResponse {
+		res := c.ToResponse()
+		for _, child := range children[c.ID] {
+			res.Replies = append(res.Replies, build(child))
+		}
+		return res
+	}
+
+	threads := make([]domain.CommentResponse, 0, len(roots))
+	for i := len(roots) - 1; i >= 0; i-- {
+		threads = append(threads, build(roots[i]))
+	}
+	return threads
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+)
+
+func TestBuildCommentThreads(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	parent := func(id int) *int { return &id }
+	deletedAt := base.Add(time.Hour)
+	comment := func(id int, parentID *int) domain.Comment {
+		at := base.Add(time.Duration(id) * time.Minute)
+		return domain.Comment{ID: id, MuseumID: 1, UserID: 100 + id, ParentID: parentID, Body: "c", CreatedAt: at, UpdatedAt: at}
+	}
+
+	// 作成日時順: 1（スレッド）→ 2（1への返信）→ 3（スレッド）→ 4（2への返信）→ 5（1への返信、削除済み）
+	deleted := comment(5, parent(1))
+	deleted.DeletedAt = &deletedAt
+	threads := buildCommentThreads([]domain.Comment{
+		comment(1, nil), comment(2, parent(1)), comment(3, nil), comment(4, parent(2)), deleted,
+	})
+
+	if len(threads) != 2 || threads[0].ID != 3 || threads[1].ID != 1 {
+		t.Fatalf("expected threads [3 1] (newest first), got %+v", threads)
+	}
+	if threads[0].Replies == nil || len(threads[0].Replies) != 0 {
+		t.Errorf("expected an empty (non-nil) reply list, got %#v", threads[0].Replies)
+	}
+
+	replies := threads[1].Replies
+	if len(replies) != 2 || replies[0].ID != 2 || replies[1].ID != 5 {
+		t.Fatalf("expected replies [2 5] (oldest first), got %+v", replies)
+	}
+	if len(replies[0].Replies) != 1 || replies[0].Replies[0].ID != 4 {
+		t.Errorf("expected reply 4 nested under 2, got %+v", replies[0].Replies)
+	}
+	// 削除済みのコメントも返信のつながりを保つため残し、本文と投稿者だけ隠す
+	if !replies[1].Deleted || replies[1].Body != "" || replies[1].UserID != 0 {
+		t.Errorf("expected a redacted deleted comment, got %+v", replies[1])
+	}
+}
+
+func TestBuildCommentThreadsEmpty(t *testing.T) {
+	if threads := buildCommentThreads(nil); threads == nil || len(threads) != 0 {
+		t.Errorf("expected an empty (non-nil) slice, got %#v", threads)
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"net/http"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+	"backend/internal/storage"
+)
+
+const coverMaxDimension = 1600 // 保存するカバー画像の幅・高さの上限
+
+var (
+	ErrCoverRequired        = errors.New("cover file is required")
+	ErrCoverTooLarge        = errors.New("cover image is too large")
+	ErrUnsupportedCoverType = errors.New("cover must be a JPEG, PNG or WebP image")
+)
+
+// coverContentTypes は受け付ける画像の種類（中身から判定したもの）
+var coverContentTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/webp": true}
+
+// CoverService はミュージアムのカバー画像のアップロードを扱う
+// 画像は中身で種類を判定し、Exifの向きを反映してから縮小・JPEGに再エンコードする（位置情報などのメタデータは残らない）
+type CoverService struct {
+	museumRepo repository.MuseumRepository
+	access     *MuseumAccess
+	store      storage.BlobStore
+	revisions  RevisionRecorder
+	maxBytes   int64
+}
+
+// NewCoverService は新しいCoverServiceを作成する。maxBytesはアップロードできる画像の上限
+func NewCoverService(museumRepo repository.MuseumRepository, access *MuseumAccess, store storage.BlobStore, revisions RevisionRecorder, maxBytes int64) *CoverService {
+	return &CoverService{museumRepo: museumRepo, access: access, store: store, revisions: revisions, maxBytes: maxBytes}
+}
+
+// MaxBytes はアップロードできる画像の上限（バイト）を返す
+func (s *CoverService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// UpdateCover はカバー画像を保存し、ミュージアムのimage_urlを保存先に更新する。変更できるのは所有者と編集者
+// versionの扱いはUpdateTitleと同じ。以前の画像はリビジョンからの復元で使われうるため消さない
+func (s *CoverService) UpdateCover(ctx context.Context, id int, userID int, file io.Reader, version int) (*domain.MuseumCoverResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+	museum, err := s.access.Authorize(ctx, id, userID, domain.PermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	// 画像の処理の前に、古いバージョンからの更新を弾いておく
+	if version > 0 && museum.Version != version {
+		return nil, errors.New("version mismatch")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrCoverTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrCoverRequired
+	}
+	cover, err := processCover(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := coverKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, cover, "image/jpeg"); err != nil {
+		return nil, fmt.Errorf("failed to store cover: %w", err)
+	}
+
+	imageURL := s.store.URL(key)
+	newVersion, err := s.museumRepo.UpdateImageURL(ctx, id, imageURL, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("museum not found")
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, errors.New("version mismatch")
+		}
+		return nil, fmt.Errorf("failed to update museum cover: %w", err)
+	}
+
+	if s.revisions != nil {
+		s.revisions.Record(ctx, id, userID, domain.RevisionUpdateCover)
+	}
+
+	return &domain.MuseumCoverResponse{ImageURL: imageURL, Version: newVersion}, nil
+}
+
+// processCover は画像の種類を中身から判定し、向きの補正・縮小をしてJPEGに再エンコードする
+func processCover(data []byte) ([]byte, error) {
+	contentType := http.DetectContentType(data)
+	if !coverContentTypes[contentType] {
+		return nil, ErrUnsupportedCoverType
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedCoverType
+	}
+	if cfg.Width*cfg.Height > imageMaxSourcePixels {
+		return nil, ErrCoverTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedCoverType
+	}
+
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+	img = resizeImage(img, coverMaxDimension, coverMaxDimension, ImageFitContain)
+
+	// 透過部分はJPEGで黒くならないよう白で塗りつぶす
+	b := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, b.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: imageJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode cover: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// coverKey は推測できない保存先のキーを作る（同じミュージアムでも毎回別のキーにし、CDNなどのキャッシュを気にしなくてよいようにする）
+func coverKey(museumID int) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("covers/%d/%s.jpg", museumID, hex.EncodeToString(b[:])), nil
+}
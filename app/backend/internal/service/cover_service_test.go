@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// jpegWithOrientation はOrientationタグだけを持つExif（APP1）をSOIの直後に差し込んだJPEGを作る
+func jpegWithOrientation(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tiff := []byte{'I', 'I', 42, 0, 8, 0, 0, 0, 1, 0}
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3) // SHORT
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0) // 次のIFDはない
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xff, 0xe1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, app1...)
+	return append(out, data[2:]...)
+}
+
+func TestProcessCoverAppliesOrientation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 300, 100))
+	data := jpegWithOrientation(t, src, 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("jpegOrientation = %d; want 6", got)
+	}
+
+	out, err := processCover(data)
+	if err != nil {
+		t.Fatalf("processCover: %v", err)
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != "jpeg" || cfg.Width != 100 || cfg.Height != 300 {
+		t.Errorf("got %s %dx%d; want jpeg 100x300", format, cfg.Width, cfg.Height)
+	}
+	// 再エンコードした画像にはExifが残らない
+	if got := jpegOrientation(out); got != 1 {
+		t.Errorf("orientation after processing = %d; want 1", got)
+	}
+}
+
+func TestProcessCoverResizesAndFlattens(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3200, 800)) // 全面透明
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := processCover(buf.Bytes())
+	if err != nil {
+		t.Fatalf("processCover: %v", err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != coverMaxDimension || b.Dy() != coverMaxDimension/4 {
+		t.Errorf("got %dx%d; want %dx%d", b.Dx(), b.Dy(), coverMaxDimension, coverMaxDimension/4)
+	}
+	if r, g, b, _ := img.At(10, 10).RGBA(); r>>8 < 250 || g>>8 < 250 || b>>8 < 250 {
+		t.Errorf("transparent pixel = %v; want white", color.RGBAModel.Convert(img.At(10, 10)))
+	}
+}
+
+func TestProcessCoverRejectsNonImages(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+		[]byte("GIF89a"),
+		{0xff, 0xd8, 0xff, 0xe0, 0x00}, // JPEGに見えるが壊れている
+	} {
+		if _, err := processCover(data); err != ErrUnsupportedCoverType {
+			t.Errorf("processCover(%q) error = %v; want ErrUnsupportedCoverType", data, err)
+		}
+	}
+}
+
+func TestApplyOrientation(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+	tests := []struct {
+		orientation int
+		w, h        int
+		redX, redY  int
+	}{
+		{1, 2, 1, 0, 0},
+		{2, 2, 1, 1, 0},
+		{3, 2, 1, 1, 0},
+		{6, 1, 2, 0, 0},
+		{8, 1, 2, 0, 1},
+	}
+	for _, tt := range tests {
+		got := applyOrientation(src, tt.orientation)
+		if b := got.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+			t.Errorf("orientation %d: got %dx%d; want %dx%d", tt.orientation, b.Dx(), b.Dy(), tt.w, tt.h)
+			continue
+		}
+		if r, _, _, _ := got.At(tt.redX, tt.redY).RGBA(); r != 0xffff {
+			t.Errorf("orientation %d: red pixel not at (%d, %d)", tt.orientation, tt.redX, tt.redY)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/domain"
+	"backend/internal/render"
+)
+
+const (
+	embedProviderName      = "バーチャル美術館"
+	embedDescriptionMax    = 200 // og:descriptionの文字数の上限（超えたら省略する）
+	oembedDefaultWidth     = 600
+	oembedPublicCacheAge   = 3600 // 公開ミュージアムの埋め込みをキャッシュしてよい秒数
+	embedPagePathPrefix    = "/m/"
+	embedAppPathPrefix     = "/museum/"
+	embedRenderPathPattern = "/api/v1/museums/%d/render.png"
+)
+
+var (
+	ErrEmbedURLNotSupported = errors.New("url is not a museum page")
+	ErrOEmbedFormat         = errors.New("only json format is supported")
+)
+
+// EmbedService は共有ページ（Open Graph・Twitterカード）とoEmbedで使うミュージアムの情報を作る
+// クローラーはログインしていないため、公開・限定公開のミュージアムと、共有リンクのトークン付きのURLだけを扱う
+type EmbedService struct {
+	museums *MuseumService
+	shares  *ShareService
+	baseURL string // このサーバーの公開URL
+	appURL  string // フロントエンドのURL
+}
+
+// NewEmbedService は新しいEmbedServiceを作成する。sharesがnilの場合はトークン付きのURLを扱わない
+func NewEmbedService(museums *MuseumService, shares *ShareService, baseURL, appURL string) *EmbedService {
+	return &EmbedService{museums: museums, shares: shares,
+		baseURL: strings.TrimSuffix(baseURL, "/"), appURL: strings.TrimSuffix(appURL, "/")}
+}
+
+// Card は共有ページに載せるミュージアムの情報を返す
+// 非公開のミュージアムは有効な共有リンクのトークンがなければ museum not found
+func (s *EmbedService) Card(ctx context.Context, museumID int, token string) (*domain.MuseumCard, error) {
+	museum, err := s.museum(ctx, museumID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	query := ""
+	if token != "" {
+		query = "?token=" + url.QueryEscape(token)
+	}
+	pageURL := s.baseURL + embedPagePathPrefix + strconv.Itoa(museum.ID) + query
+	card := &domain.MuseumCard{
+		MuseumID:    museum.ID,
+		Title:       museum.Name,
+		Description: embedDescription(museum),
+		PageURL:     pageURL,
+		AppURL:      s.appURL + embedAppPathPrefix + strconv.Itoa(museum.ID) + query, // トークンはAPIの?token=と同じ形で引き継ぐ
+		OEmbedURL:   s.baseURL + "/oembed?format=json&url=" + url.QueryEscape(pageURL),
+		RenderURL:   s.baseURL + fmt.Sprintf(embedRenderPathPattern, museum.ID) + query,
+		Indexable:   museum.Visibility == domain.VisibilityPublic && token == "",
+	}
+	// アップロードしたカバー画像があればそれを使う（クローラーが取得できる絶対URLのみ）
+	if u, err := url.Parse(museum.ImageURL); err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" {
+		card.ImageURL = museum.ImageURL
+	} else {
+		card.ImageURL, card.ImageWidth, card.ImageHeight = card.RenderURL, render.Width, render.Height
+	}
+	return card, nil
+}
+
+// OEmbed はミュージアムのページのURL（共有ページ/m/{id}またはフロントエンドの/museum/{id}）からoEmbedのレスポンスを作る
+// 埋め込むHTMLは部屋の合成画像をフロントエンドへのリンクにしたもので、maxWidth・maxHeight（0は指定なし）に収める
+func (s *EmbedService) OEmbed(ctx context.Context, rawURL string, format string, maxWidth, maxHeight int) (*domain.OEmbedResponse, error) {
+	if format != "" && format != "json" {
+		return nil, ErrOEmbedFormat
+	}
+	museumID, token, err := s.parsePageURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	card, err := s.Card(ctx, museumID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := fitEmbedSize(maxWidth, maxHeight)
+	resp := &domain.OEmbedResponse{
+		Version:      "1.0",
+		Type:         "rich",
+		Title:        card.Title,
+		ProviderName: embedProviderName,
+		ProviderURL:  s.appURL,
+		HTML: fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener"><img src="%s" width="%d" height="%d" alt="%s" style="max-width:100%%;height:auto"></a>`,
+			html.EscapeString(card.AppURL), html.EscapeString(card.RenderURL), width, height, html.EscapeString(card.Title)),
+		Width:           width,
+		Height:          height,
+		ThumbnailURL:    card.RenderURL,
+		ThumbnailWidth:  render.Width,
+		ThumbnailHeight: render.Height,
+	}
+	if card.Indexable {
+		resp.CacheAge = oembedPublicCacheAge
+	}
+	return resp, nil
+}
+
+// museum は公開設定に従ってミュージアムを取得する（クローラーなのでユーザーIDはない）
+func (s *EmbedService) museum(ctx context.Context, museumID int, token string) (*domain.MuseumResponse, error) {
+	if token == "" || s.shares == nil {
+		return s.museums.GetMuseumByID(ctx, museumID, 0)
+	}
+	share, err := s.shares.Resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if share.MuseumID != museumID {
+		return nil, errors.New("share link not found")
+	}
+	return s.museums.GetSharedMuseum(ctx, museumID, 0)
+}
+
+// parsePageURL はこのサーバーの共有ページかフロントエンドの閲覧画面のURLから、ミュージアムIDと共有リンクのトークンを取り出す
+func (s *EmbedService) parsePageURL(rawURL string) (int, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return 0, "", ErrEmbedURLNotSupported
+	}
+	var rest string
+	switch {
+	case sameOrigin(u, s.baseURL) && strings.HasPrefix(u.Path, embedPagePathPrefix):
+		rest = strings.TrimPrefix(u.Path, embedPagePathPrefix)
+	case sameOrigin(u, s.appURL) && strings.HasPrefix(u.Path, embedAppPathPrefix):
+		rest = strings.TrimPrefix(u.Path, embedAppPathPrefix)
+	default:
+		return 0, "", ErrEmbedURLNotSupported
+	}
+	id, err := strconv.Atoi(strings.TrimSuffix(rest, "/"))
+	if err != nil || id <= 0 {
+		return 0, "", ErrEmbedURLNotSupported
+	}
+	return id, u.Query().Get("token"), nil
+}
+
+// sameOrigin はURLがbaseと同じホストを指しているか確認する（httpとhttpsの違いは問わない）
+func sameOrigin(u *url.URL, base string) bool {
+	b, err := url.Parse(base)
+	return err == nil && strings.EqualFold(u.Host, b.Host)
+}
+
+// embedDescription は説明文を概要に使う。長い場合は省略し、空なら既定の文にする
+func embedDescription(museum *domain.MuseumResponse) string {
+	desc := strings.Join(strings.Fields(museum.Description), " ")
+	if desc == "" {
+		return fmt.Sprintf("「%s」の展示 - %s", museum.Name, embedProviderName)
+	}
+	if utf8.RuneCountInString(desc) > embedDescriptionMax {
+		desc = string([]rune(desc)[:embedDescriptionMax-1]) + "…"
+	}
+	return desc
+}
+
+// fitEmbedSize は合成画像の縦横比を保ったまま、埋め込みの大きさをmaxWidth・maxHeightに収める
+func fitEmbedSize(maxWidth, maxHeight int) (int, int) {
+	width := oembedDefaultWidth
+	if maxWidth > 0 && maxWidth < width {
+		width = maxWidth
+	}
+	height := width * render.Height / render.Width
+	if maxHeight > 0 && height > maxHeight {
+		height = maxHeight
+		width = height * render.Width / render.Height
+	}
+	return max(width, 1), max(height, 1)
+}
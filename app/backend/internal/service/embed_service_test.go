@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"backend/internal/domain"
+)
+
+func TestParsePageURL(t *testing.T) {
+	s := NewEmbedService(nil, nil, "https://api.example.com", "https://museum.example.com/")
+	tests := []struct {
+		url       string
+		wantID    int
+		wantToken string
+		wantErr   bool
+	}{
+		{"https://api.example.com/m/12", 12, "", false},
+		{"http://api.example.com/m/12/", 12, "", false},
+		{"https://api.example.com/m/12?token=abc", 12, "abc", false},
+		{"https://museum.example.com/museum/7", 7, "", false},
+		{"https://museum.example.com/m/7", 0, "", true},    // 共有ページはAPIのホストのみ
+		{"https://evil.example.com/m/12", 0, "", true},     // 別のホスト
+		{"https://api.example.com/m/0", 0, "", true},       // 不正なID
+		{"https://api.example.com/m/12/edit", 0, "", true}, // 余分なパス
+		{"/m/12", 0, "", true},
+	}
+	for _, tt := range tests {
+		id, token, err := s.parsePageURL(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePageURL(%q) error = %v; wantErr %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if id != tt.wantID || token != tt.wantToken {
+			t.Errorf("parsePageURL(%q) = %d, %q; want %d, %q", tt.url, id, token, tt.wantID, tt.wantToken)
+		}
+	}
+}
+
+func TestFitEmbedSize(t *testing.T) {
+	tests := []struct {
+		maxW, maxH   int
+		wantW, wantH int
+	}{
+		{0, 0, 600, 388},
+		{300, 0, 300, 194},
+		{1000, 0, 600, 388}, // 既定より大きくはしない
+		{0, 194, 300, 194},
+		{400, 100, 154, 100},
+	}
+	for _, tt := range tests {
+		w, h := fitEmbedSize(tt.maxW, tt.maxH)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("fitEmbedSize(%d, %d) = %dx%d; want %dx%d", tt.maxW, tt.maxH, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestEmbedDescription(t *testing.T) {
+	if got := embedDescription(&domain.MuseumResponse{Name: "印象派", Description: "  光と\n色彩  "}); got != "光と 色彩" {
+		t.Errorf("got %q", got)
+	}
+	if got := embedDescription(&domain.MuseumResponse{Name: "印象派"}); !strings.Contains(got, "印象派") {
+		t.Errorf("empty description should fall back to the name, got %q", got)
+	}
+	long := embedDescription(&domain.MuseumResponse{Description: strings.Repeat("絵", 300)})
+	if n := utf8.RuneCountInString(long); n != embedDescriptionMax || !strings.HasSuffix(long, "…") {
+		t.Errorf("long description has %d runes (%q...)", n, string([]rune(long)[:5]))
+	}
+}
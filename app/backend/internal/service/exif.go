@@ -0,0 +1,116 @@
+package service
+
+import (
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// jpegOrientation はJPEGのExif（APP1）からOrientationタグ（1〜8）を読み取る。なければ1を返す
+// 再エンコードでExifは消えるため、向きだけは先に画素へ反映しておく必要がある
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xff || data[1] != 0xd8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xff {
+			return 1
+		}
+		marker := data[i+1]
+		// SOS以降は画像データなのでExifはない
+		if marker == 0xda || marker == 0xd9 {
+			return 1
+		}
+		size := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if size < 2 || i+2+size > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+size]
+		if marker == 0xe1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + size
+	}
+	return 1
+}
+
+// tiffOrientation はExifのTIFF構造の最初のIFDからOrientationを探す
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for n := 0; n < entries; n++ {
+		e := ifd + 2 + n*12
+		if e+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[e:e+2]) != 0x0112 {
+			continue
+		}
+		if v := int(order.Uint16(tiff[e+8 : e+10])); v >= 1 && v <= 8 {
+			return v
+		}
+		return 1
+	}
+	return 1
+}
+
+// applyOrientation はExifのOrientationに従って画像を回転・反転する
+func applyOrientation(src image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w // 5〜8は90度回転を含むため縦横が入れ替わる
+	}
+
+	// 元画像の座標を変換先の座標から求める
+	srcAt := func(x, y int) (int, int) {
+		switch orientation {
+		case 2: // 左右反転
+			return w - 1 - x, y
+		case 3: // 180度回転
+			return w - 1 - x, h - 1 - y
+		case 4: // 上下反転
+			return x, h - 1 - y
+		case 5: // 左上と右下を結ぶ線で反転
+			return y, x
+		case 6: // 時計回りに90度回転
+			return y, h - 1 - x
+		case 7: // 右上と左下を結ぶ線で反転
+			return w - 1 - y, h - 1 - x
+		default: // 8: 反時計回りに90度回転
+			return w - 1 - y, x
+		}
+	}
+
+	rgba := image.NewRGBA(b)
+	draw.Draw(rgba, b, src, b.Min, draw.Src)
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := srcAt(x, y)
+			si := rgba.PixOffset(b.Min.X+sx, b.Min.Y+sy)
+			di := dst.PixOffset(x, y)
+			copy(dst.Pix[di:di+4], rgba.Pix[si:si+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+const (
+	exportImageWidth  = 1600   // 書き出す作品画像の幅の上限
+	exportConcurrency = 4      // 作品画像を同時に取得する数
+	exportUntitled    = "作品 #" // 作品情報を取得できなかった場合のタイトル（作品IDを続ける）
+)
+
+var (
+	//go:embed templates/export_index.html
+	exportIndexHTML     string
+	exportIndexTemplate = template.Must(template.New("index").Parse(exportIndexHTML))
+
+	//go:embed templates/export_style.css
+	exportStyleCSS []byte
+)
+
+// 画像を含めなかった理由（ページにそのまま表示する）
+const (
+	exportNoteNoObject    = "作品情報を取得できませんでした"
+	exportNoteNotPublic   = "パブリックドメインではないため、画像は含まれていません"
+	exportNoteNoImage     = "画像がありません"
+	exportNoteImageFailed = "画像を取得できませんでした"
+)
+
+// ExportService はミュージアムを静的なHTMLギャラリー（ZIP）として書き出す
+// 作品情報はMET APIから取得し、パブリックドメインの作品だけ画像を含める
+type ExportService struct {
+	museumRepo  repository.MuseumRepository
+	artworkRepo repository.MuseumArtworkRepository
+	access      *MuseumAccess
+	met         *MetService
+	images      *ImageService
+	log         *slog.Logger
+}
+
+// NewExportService は新しいExportServiceを作成する
+func NewExportService(museumRepo repository.MuseumRepository, artworkRepo repository.MuseumArtworkRepository, access *MuseumAccess,
+	met *MetService, images *ImageService, log *slog.Logger) *ExportService {
+	return &ExportService{museumRepo: museumRepo, artworkRepo: artworkRepo, access: access, met: met, images: images, log: log}
+}
+
+// MuseumExport は書き出すミュージアムと、作品情報・画像を取得済みの展示作品
+type MuseumExport struct {
+	Museum      domain.Museum
+	Artworks    []ExportedArtwork
+	GeneratedAt time.Time
+}
+
+// ExportedArtwork は書き出す展示作品1件
+type ExportedArtwork struct {
+	ObjectID  int
+	Title     string
+	Caption   string     // museums_to_arts.description
+	Object    *MetObject // 取得できなかった場合はnil
+	ImagePath string     // ZIP内の画像のパス（画像を含めない場合は空）
+	ImageNote string     // 画像を含めなかった理由
+	image     []byte
+}
+
+// FileName はダウンロード時のファイル名
+func (e *MuseumExport) FileName() string {
+	return fmt.Sprintf("museum-%d.zip", e.Museum.ID)
+}
+
+// Prepare は閲覧できるユーザーのためにミュージアムの書き出しを準備する（作品情報と画像をすべて取得する）
+// 書き出しを始める前にエラーを返せるよう、ZIPの書き込みはWriteZipで別に行う
+func (s *ExportService) Prepare(ctx context.Context, museumID int, callerID int) (*MuseumExport, error) {
+	return s.prepare(ctx, museumID, callerID, false)
+}
+
+// PrepareShared は公開設定を問わずミュージアムの書き出しを準備する
+// 共有リンク経由（検証は呼び出し側で済んでいる前提）と、サーバーを操作できる管理者のCLIから使う
+func (s *ExportService) PrepareShared(ctx context.Context, museumID int) (*MuseumExport, error) {
+	return s.prepare(ctx, museumID, 0, true)
+}
+
+func (s *ExportService) prepare(ctx context.Context, museumID int, callerID int, shared bool) (*MuseumExport, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+
+	var museum *domain.Museum
+	var err error
+	if shared {
+		museum, err = s.museumRepo.FindByID(ctx, museumID)
+		if err == nil && museum == nil {
+			err = errors.New("museum not found")
+		}
+	} else {
+		museum, err = s.access.Authorize(ctx, museumID, callerID, domain.PermissionView)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	placements, err := s.artworkRepo.ListByMuseum(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artworks: %w", err)
+	}
+	ids := make([]int, len(placements))
+	for i, p := range placements {
+		ids[i] = p.ObjectID
+	}
+	batch, err := s.met.GetObjectsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	objects := make(map[int]MetObject, len(batch.Objects))
+	for _, obj := range batch.Objects {
+		objects[obj.ObjectID] = obj
+	}
+
+	export := &MuseumExport{Museum: *museum, Artworks: make([]ExportedArtwork, len(placements)), GeneratedAt: time.Now()}
+	for i, p := range placements {
+		a := ExportedArtwork{ObjectID: p.ObjectID, Title: fmt.Sprintf("%s%d", exportUntitled, p.ObjectID), Caption: p.Description}
+		if obj, ok := objects[p.ObjectID]; ok {
+			a.Object = &obj
+			if obj.Title != "" {
+				a.Title = obj.Title
+			}
+		}
+		export.Artworks[i] = a
+	}
+	s.fetchImages(ctx, export.Artworks)
+	return export, nil
+}
+
+// fetchImages はパブリックドメインの作品の画像を取得する。取得できなかった作品は理由を残して画像なしで書き出す
+func (s *ExportService) fetchImages(ctx context.Context, artworks []ExportedArtwork) {
+	sem := make(chan struct{}, exportConcurrency)
+	var wg sync.WaitGroup
+	for i := range artworks {
+		a := &artworks[i]
+		switch {
+		case a.Object == nil:
+			a.ImageNote = exportNoteNoObject
+			continue
+		case !a.Object.IsPublicDomain:
+			a.ImageNote = exportNoteNotPublic
+			continue
+		case a.Object.PrimaryImage == "" && a.Object.PrimaryImageSmall == "":
+			a.ImageNote = exportNoteNoImage
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			img, err := s.images.GetImage(ctx, a.ObjectID, ImageOptions{Width: exportImageWidth, Format: ImageFormatJPEG})
+			if err != nil {
+				a.ImageNote = exportNoteImageFailed
+				if errors.Is(err, ErrArtworkHasNoImage) {
+					a.ImageNote = exportNoteNoImage
+				} else if s.log != nil {
+					s.log.Warn("failed to fetch artwork image for export",
+						slog.Int("objectId", a.ObjectID),
+						slog.String("error", err.Error()),
+					)
+				}
+				return
+			}
+			a.image = img.Data
+			a.ImagePath = fmt.Sprintf("images/%d.jpg", a.ObjectID)
+		}()
+	}
+	wg.Wait()
+}
+
+// exportManifest はZIPに含めるmuseum.json（ほかのツールで読み込めるよう作品情報をまとめたもの）
+type exportManifest struct {
+	Museum struct {
+		ID          int    `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"museum"`
+	GeneratedAt time.Time               `json:"generatedAt"`
+	Artworks    []exportManifestArtwork `json:"artworks"`
+}
+
+type exportManifestArtwork struct {
+	ObjectID       int    `json:"objectId"`
+	Title          string `json:"title"`
+	Artist         string `json:"artist,omitempty"`
+	ObjectDate     string `json:"objectDate,omitempty"`
+	Medium         string `json:"medium,omitempty"`
+	CreditLine     string `json:"creditLine,omitempty"`
+	ObjectURL      string `json:"objectUrl,omitempty"`
+	IsPublicDomain bool   `json:"isPublicDomain"`
+	Caption        string `json:"caption,omitempty"`
+	Image          string `json:"image,omitempty"`
+}
+
+// exportFile はZIPに含めるファイル1件
+type exportFile struct {
+	name   string
+	data   []byte
+	method uint16
+}
+
+// WriteZip はindex.html・style.css・museum.jsonと作品画像（images/{objectId}.jpg）をZIPにしてwに書き込む
+func (e *MuseumExport) WriteZip(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	var index bytes.Buffer
+	if err := exportIndexTemplate.Execute(&index, e); err != nil {
+		return fmt.Errorf("render index.html: %w", err)
+	}
+	manifest, err := json.MarshalIndent(e.manifest(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	files := []exportFile{
+		{"index.html", index.Bytes(), zip.Deflate},
+		{"style.css", exportStyleCSS, zip.Deflate},
+		{"museum.json", manifest, zip.Deflate},
+	}
+	for _, a := range e.Artworks {
+		if a.ImagePath != "" {
+			// JPEGは圧縮済みなのでそのまま格納する
+			files = append(files, exportFile{a.ImagePath, a.image, zip.Store})
+		}
+	}
+
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: f.method, Modified: e.GeneratedAt})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (e *MuseumExport) manifest() exportManifest {
+	var m exportManifest
+	m.Museum.ID = e.Museum.ID
+	m.Museum.Name = e.Museum.Name
+	m.Museum.Description = e.Museum.Description
+	m.GeneratedAt = e.GeneratedAt
+	m.Artworks = make([]exportManifestArtwork, len(e.Artworks))
+	for i, a := range e.Artworks {
+		ma := exportManifestArtwork{ObjectID: a.ObjectID, Title: a.Title, Caption: a.Caption, Image: a.ImagePath}
+		if o := a.Object; o != nil {
+			ma.Artist, ma.ObjectDate, ma.Medium = o.ArtistDisplayName, o.ObjectDate, o.Medium
+			ma.CreditLine, ma.ObjectURL, ma.IsPublicDomain = o.CreditLine, o.ObjectURL, o.IsPublicDomain
+		}
+		m.Artworks[i] = ma
+	}
+	return m
+}
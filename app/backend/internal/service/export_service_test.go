@@ -0,0 +1,104 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+)
+
+func TestFetchImagesSkipsNonPublicDomain(t *testing.T) {
+	artworks := []ExportedArtwork{
+		{ObjectID: 1},
+		{ObjectID: 2, Object: &MetObject{ObjectID: 2, IsPublicDomain: false, PrimaryImage: "https://images.example.com/2.jpg"}},
+		{ObjectID: 3, Object: &MetObject{ObjectID: 3, IsPublicDomain: true}},
+	}
+	// 画像を取得する作品がないため、ImageServiceはnilでよい
+	(&ExportService{}).fetchImages(context.Background(), artworks)
+
+	for i, want := range []string{exportNoteNoObject, exportNoteNotPublic, exportNoteNoImage} {
+		if artworks[i].ImagePath != "" || artworks[i].ImageNote != want {
+			t.Errorf("artwork %d: path %q, note %q; want no image with note %q", artworks[i].ObjectID, artworks[i].ImagePath, artworks[i].ImageNote, want)
+		}
+	}
+}
+
+func TestWriteZip(t *testing.T) {
+	export := &MuseumExport{
+		Museum: domain.Museum{ID: 7, Name: "印象派 <展>", Description: "光の表現"},
+		Artworks: []ExportedArtwork{
+			{
+				ObjectID: 436535, Title: "Wheat Field with Cypresses", Caption: "糸杉のうねり",
+				Object: &MetObject{ObjectID: 436535, ArtistDisplayName: "Vincent van Gogh", IsPublicDomain: true,
+					CreditLine: "Purchase, The Annenberg Foundation Gift, 1993", ObjectURL: "https://www.metmuseum.org/art/collection/search/436535"},
+				ImagePath: "images/436535.jpg", image: []byte("jpeg"),
+			},
+			{
+				ObjectID: 12345, Title: "Modern Work", Caption: "著作権のある作品",
+				Object:    &MetObject{ObjectID: 12345, IsPublicDomain: false, CreditLine: "Gift of Someone, 2001"},
+				ImageNote: exportNoteNotPublic,
+			},
+		},
+		GeneratedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	if got := export.FileName(); got != "museum-7.zip" {
+		t.Errorf("FileName() = %q", got)
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteZip(&buf); err != nil {
+		t.Fatalf("WriteZip: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	for _, name := range []string{"index.html", "style.css", "museum.json", "images/436535.jpg"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("zip does not contain %s", name)
+		}
+	}
+	if _, ok := files["images/12345.jpg"]; ok {
+		t.Errorf("zip contains the image of a non-public-domain artwork")
+	}
+
+	index := files["index.html"]
+	for _, want := range []string{
+		"印象派 &lt;展&gt;",
+		`src="images/436535.jpg"`,
+		"糸杉のうねり",
+		"Purchase, The Annenberg Foundation Gift, 1993",
+		"https://www.metmuseum.org/art/collection/search/436535",
+		"Gift of Someone, 2001",
+		exportNoteNotPublic,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %q", want)
+		}
+	}
+
+	var manifest exportManifest
+	if err := json.Unmarshal([]byte(files["museum.json"]), &manifest); err != nil {
+		t.Fatalf("museum.json: %v", err)
+	}
+	if manifest.Museum.ID != 7 || len(manifest.Artworks) != 2 || manifest.Artworks[1].Image != "" || manifest.Artworks[0].Caption != "糸杉のうねり" {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+}
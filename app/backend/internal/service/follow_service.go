@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// FollowService はユーザーのフォロー関係のビジネスロジックを含む
+type FollowService struct {
+	repo repository.FollowRepository
+}
+
+// NewFollowService は新しいFollowServiceを作成する
+func NewFollowService(repo repository.FollowRepository) *FollowService {
+	return &FollowService{repo: repo}
+}
+
+// Follow はfollowerIDのユーザーがfolloweeIDのユーザーをフォローする（冪等）
+func (s *FollowService) Follow(ctx context.Context, followerID, followeeID int) (*domain.FollowResponse, error) {
+	if err := s.validatePair(ctx, followerID, followeeID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Follow(ctx, followerID, followeeID); err != nil {
+		return nil, fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return s.followResponse(ctx, followerID, followeeID)
+}
+
+// Unfollow はフォローを解除する（冪等）
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followeeID int) (*domain.FollowResponse, error) {
+	if err := s.validatePair(ctx, followerID, followeeID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Unfollow(ctx, followerID, followeeID); err != nil {
+		return nil, fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return s.followResponse(ctx, followerID, followeeID)
+}
+
+// ListFollowers は指定ユーザーのフォロワー一覧を取得する
+func (s *FollowService) ListFollowers(ctx context.Context, userID int, limit, offset int) (*domain.FollowListResponse, error) {
+	return s.list(ctx, userID, limit, offset, s.repo.ListFollowers)
+}
+
+// ListFollowing は指定ユーザーがフォローしているユーザーの一覧を取得する
+func (s *FollowService) ListFollowing(ctx context.Context, userID int, limit, offset int) (*domain.FollowListResponse, error) {
+	return s.list(ctx, userID, limit, offset, s.repo.ListFollowing)
+}
+
+// list はフォロー一覧の取得処理を共通化する
+func (s *FollowService) list(ctx context.Context, userID int, limit, offset int, fetch func(context.Context, int, int, int) ([]domain.FollowUser, int, error)) (*domain.FollowListResponse, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20 // デフォルト値
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := fetch(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follows: %w", err)
+	}
+
+	return &domain.FollowListResponse{
+		UserID: userID,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Users:  users,
+	}, nil
+}
+
+// validatePair はフォロー操作の対象を検証する
+func (s *FollowService) validatePair(ctx context.Context, followerID, followeeID int) error {
+	if followerID <= 0 {
+		return errors.New("invalid user ID")
+	}
+	if followerID == followeeID {
+		return errors.New("cannot follow yourself")
+	}
+	return s.ensureUserExists(ctx, followeeID)
+}
+
+// ensureUserExists はユーザーが存在するか確認する
+func (s *FollowService) ensureUserExists(ctx context.Context, userID int) error {
+	if userID <= 0 {
+		return errors.New("invalid user ID")
+	}
+
+	exists, err := s.repo.UserExists(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	if !exists {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// followResponse はフォロー操作後の状態を返す
+func (s *FollowService) followResponse(ctx context.Context, followerID, followeeID int) (*domain.FollowResponse, error) {
+	following, err := s.repo.IsFollowing(ctx, followerID, followeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get follow state: %w", err)
+	}
+
+	count, err := s.repo.CountFollowers(ctx, followeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count followers: %w", err)
+	}
+
+	return &domain.FollowResponse{
+		UserID:        followeeID,
+		Following:     following,
+		FollowerCount: count,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// memoryFollowRepo はメモリ上でフォロー関係を保持するFollowRepository
+type memoryFollowRepo struct {
+	repository.FollowRepository
+	users     map[int]bool
+	follows   map[[2]int]bool // {フォローする側, される側}
+	lastLimit int
+}
+
+func newMemoryFollowRepo(userIDs ...int) *memoryFollowRepo {
+	r := &memoryFollowRepo{users: map[int]bool{}, follows: map[[2]int]bool{}}
+	for _, id := range userIDs {
+		r.users[id] = true
+	}
+	return r
+}
+
+func (r *memoryFollowRepo) UserExists(_ context.Context, userID int) (bool, error) {
+	return r.users[userID], nil
+}
+
+func (r *memoryFollowRepo) Follow(_ context.Context, followerID, followeeID int) error {
+	r.follows[[2]int{followerID, followeeID}] = true
+	return nil
+}
+
+func (r *memoryFollowRepo) Unfollow(_ context.Context, followerID, followeeID int) error {
+	delete(r.follows, [2]int{followerID, followeeID})
+	return nil
+}
+
+func (r *memoryFollowRepo) IsFollowing(_ context.Context, followerID, followeeID int) (bool, error) {
+	return r.follows[[2]int{followerID, followeeID}], nil
+}
+
+func (r *memoryFollowRepo) CountFollowers(_ context.Context, userID int) (int, error) {
+	count := 0
+	for pair := range r.follows {
+		if pair[1] == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryFollowRepo) ListFollowers(_ context.Context, _ int, limit, _ int) ([]domain.FollowUser, int, error) {
+	r.lastLimit = limit
+	return []domain.FollowUser{}, 0, nil
+}
+
+func TestFollowYourself(t *testing.T) {
+	s := NewFollowService(newMemoryFollowRepo(1))
+	if _, err := s.Follow(context.Background(), 1, 1); err == nil || err.Error() != "cannot follow yourself" {
+		t.Errorf("expected cannot follow yourself, got %v", err)
+	}
+}
+
+func TestFollowIsIdempotent(t *testing.T) {
+	s := NewFollowService(newMemoryFollowRepo(1, 2))
+	for i := 0; i < 2; i++ {
+		res, err := s.Follow(context.Background(), 1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 2回目のフォローでもフォロワー数は増えない
+		if !res.Following || res.FollowerCount != 1 {
+			t.Errorf("follow #%d: got %+v, want following with 1 follower", i+1, res)
+		}
+	}
+
+	res, err := s.Unfollow(context.Background(), 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Following || res.FollowerCount != 0 {
+		t.Errorf("unfollow: got %+v, want not following with 0 followers", res)
+	}
+}
+
+func TestFollowMissingUser(t *testing.T) {
+	s := NewFollowService(newMemoryFollowRepo(1))
+	if _, err := s.Follow(context.Background(), 1, 99); err == nil || err.Error() != "user not found" {
+		t.Errorf("expected user not found, got %v", err)
+	}
+}
+
+func TestListFollowersClampsLimit(t *testing.T) {
+	repo := newMemoryFollowRepo(1)
+	s := NewFollowService(repo)
+	for _, tc := range []struct{ limit, want int }{{0, 20}, {-1, 20}, {101, 20}, {100, 100}, {5, 5}} {
+		res, err := s.ListFollowers(context.Background(), 1, tc.limit, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Limit != tc.want || repo.lastLimit != tc.want {
+			t.Errorf("limit %d: got %d (repository %d), want %d", tc.limit, res.Limit, repo.lastLimit, tc.want)
+		}
+	}
+}
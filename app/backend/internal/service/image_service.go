@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // METの画像はほぼJPEGだが、PNGの作品もデコードできるようにする
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"golang.org/x/sync/singleflight"
+
+	"backend/internal/logger"
+	"backend/internal/metclient"
+	"backend/internal/metrics"
+	"backend/internal/repository"
+	"backend/internal/tracing"
+	"backend/internal/validate"
+)
+
+const (
+	imageMaxDimension    = 2000       // 縮小後の幅・高さの上限
+	imageSmallSourceMax  = 600        // 要求サイズがこれ以下ならprimaryImageSmallを元画像に使う
+	imageMaxSourceBytes  = 30 << 20   // 元画像のダウンロードサイズの上限
+	imageMaxSourcePixels = 60_000_000 // デコードする元画像の画素数の上限（巨大画像でメモリを使い切らないため）
+	imageJPEGQuality     = 85
+	imageCacheVersion    = "v1" // 縮小・エンコードの方法を変えたら上げて、古いキャッシュを使わないようにする
+)
+
+// ImageFit は縮小の仕方
+type ImageFit string
+
+const (
+	ImageFitContain ImageFit = "contain" // 縦横比を保ったまま枠に収める
+	ImageFitCover   ImageFit = "cover"   // 縦横比を保ったまま枠を埋め、はみ出した部分を中央で切り取る
+)
+
+// ImageFormat は出力形式
+type ImageFormat string
+
+const (
+	ImageFormatJPEG ImageFormat = "jpeg"
+	ImageFormatWebP ImageFormat = "webp" // 可逆圧縮（canvasでの合成向け）
+)
+
+// ContentType returns the MIME type of the format.
+func (f ImageFormat) ContentType() string {
+	if f == ImageFormatWebP {
+		return "image/webp"
+	}
+	return "image/jpeg"
+}
+
+// ErrArtworkHasNoImage はMETの作品に画像がない（著作権の都合で公開されていないなど）場合に返される
+var ErrArtworkHasNoImage = errors.New("artwork has no image")
+
+// ImageOptions は画像の縮小・変換の指定
+// 幅・高さは0なら指定なし（もう一方に合わせる）、どちらもなければ元の大きさ（上限imageMaxDimension）
+type ImageOptions struct {
+	Width  int         `json:"w" validate:"min=0,max=2000"`
+	Height int         `json:"h" validate:"min=0,max=2000"`
+	Fit    ImageFit    `json:"fit" validate:"oneof=contain cover"`
+	Format ImageFormat `json:"format" validate:"oneof=jpeg webp"`
+}
+
+// withDefaults は省略された項目に既定値を入れる
+func (o ImageOptions) withDefaults() ImageOptions {
+	if o.Fit == "" {
+		o.Fit = ImageFitContain
+	}
+	if o.Format == "" {
+		o.Format = ImageFormatJPEG
+	}
+	return o
+}
+
+// cacheKey は作品IDと指定からキャッシュのキーを作る（ETagにも使う）
+func (o ImageOptions) cacheKey(objectID int) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s:%d:%dx%d:%s:%s", imageCacheVersion, objectID, o.Width, o.Height, o.Fit, o.Format))
+	return hex.EncodeToString(sum[:])
+}
+
+// Image は縮小・再エンコード済みの画像
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string
+}
+
+// ImageService はMETの作品画像を取得し、縮小・再エンコードしてキャッシュする
+type ImageService struct {
+	met      *MetService
+	client   *metclient.Client
+	cache    repository.ImageCacheRepository // nilの場合はキャッシュしない
+	log      *slog.Logger                    // リクエストのロガーがない場合に使う（nilの場合はログを出さない）
+	inflight singleflight.Group
+}
+
+// NewImageService は新しいImageServiceを作成する
+// clientは画像のダウンロード用（MET APIとは別のホストなので、ブレーカーやレート制限を分ける）
+func NewImageService(met *MetService, client *metclient.Client, cache repository.ImageCacheRepository, log *slog.Logger) *ImageService {
+	return &ImageService{met: met, client: client, cache: cache, log: log}
+}
+
+// GetImage は作品画像を指定の大きさ・形式で返す
+// 同じ作品・指定の変換が同時に要求された場合は1回だけ処理する
+func (s *ImageService) GetImage(ctx context.Context, objectID int, opts ImageOptions) (img *Image, err error) {
+	if err := validate.Struct(opts); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	ctx, span := tracing.Start(ctx, "ImageService.GetImage",
+		attribute.Int("met.object_id", objectID),
+		attribute.Int("image.width", opts.Width),
+		attribute.Int("image.height", opts.Height),
+		attribute.String("image.fit", string(opts.Fit)),
+		attribute.String("image.format", string(opts.Format)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	key := opts.cacheKey(objectID)
+	result := &Image{ContentType: opts.Format.ContentType(), ETag: `"` + key[:32] + `"`}
+
+	if s.cache != nil {
+		data, err := s.cache.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		metrics.CacheResult("image", data != nil)
+		span.SetAttributes(attribute.Bool("cache.hit", data != nil))
+		if data != nil {
+			result.Data = data
+			return result, nil
+		}
+	}
+
+	v, err, _ := s.inflight.Do(key, func() (any, error) {
+		// 待っている他のリクエストのため、最初の呼び出し元が切断しても最後まで処理する
+		ctx := context.WithoutCancel(ctx)
+		data, err := s.render(ctx, objectID, opts)
+		if err != nil {
+			return nil, err
+		}
+		// キャッシュに保存できなくても画像は返せるため、警告のログに留める
+		if s.cache != nil {
+			if err := s.cache.Put(ctx, key, data); err != nil {
+				if log := logger.FromContext(ctx, s.log); log != nil {
+					log.Warn("failed to store image in cache",
+						slog.String("error", err.Error()),
+						slog.Int("objectId", objectID),
+					)
+				}
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Data = v.([]byte)
+	return result, nil
+}
+
+// render は元画像をダウンロードして縮小・エンコードする
+func (s *ImageService) render(ctx context.Context, objectID int, opts ImageOptions) ([]byte, error) {
+	obj, err := s.met.GetObjectByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	src := obj.PrimaryImage
+	if obj.PrimaryImageSmall != "" && opts.Width <= imageSmallSourceMax && opts.Height <= imageSmallSourceMax &&
+		(opts.Width > 0 || opts.Height > 0) {
+		src = obj.PrimaryImageSmall
+	}
+	if src == "" {
+		return nil, ErrArtworkHasNoImage
+	}
+
+	decoded, err := s.download(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	resized := resizeImage(decoded, opts.Width, opts.Height, opts.Fit)
+
+	var buf bytes.Buffer
+	switch opts.Format {
+	case ImageFormatWebP:
+		err = nativewebp.Encode(&buf, resized, nil)
+	default:
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: imageJPEGQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", opts.Format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// download は画像をダウンロードしてデコードする
+func (s *ImageService) download(ctx context.Context, url string) (image.Image, error) {
+	resp, err := s.client.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("MET image returned %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, imageMaxSourceBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > imageMaxSourceBytes {
+		return nil, fmt.Errorf("MET image is larger than %d bytes", imageMaxSourceBytes)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode MET image: %w", err)
+	}
+	if cfg.Width*cfg.Height > imageMaxSourcePixels {
+		return nil, fmt.Errorf("MET image is too large (%dx%d)", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode MET image: %w", err)
+	}
+	return img, nil
+}
+
+// resizeImage は画像を幅w・高さhに合わせて縮小する（拡大はしない）
+// containは枠に収め、coverは枠の縦横比で中央を切り取ってから縮小する（w・hの一方が0ならcontainと同じ）
+func resizeImage(src image.Image, w, h int, fit ImageFit) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if sw == 0 || sh == 0 {
+		return src
+	}
+	if w == 0 && h == 0 {
+		w, h = imageMaxDimension, imageMaxDimension
+	}
+
+	crop := b
+	var dw, dh int
+	if fit == ImageFitCover && w > 0 && h > 0 {
+		// 元画像から枠と同じ縦横比の領域を中央で切り取る
+		cw, ch := sw, sw*h/w
+		if ch > sh {
+			cw, ch = sh*w/h, sh
+		}
+		x0, y0 := b.Min.X+(sw-cw)/2, b.Min.Y+(sh-ch)/2
+		crop = image.Rect(x0, y0, x0+cw, y0+ch)
+		dw, dh = w, h
+		if cw < w {
+			dw, dh = cw, ch
+		}
+	} else {
+		scale := 1.0
+		if w > 0 {
+			scale = min(scale, float64(w)/float64(sw))
+		}
+		if h > 0 {
+			scale = min(scale, float64(h)/float64(sh))
+		}
+		dw, dh = max(1, int(float64(sw)*scale+0.5)), max(1, int(float64(sh)*scale+0.5))
+	}
+
+	if dw == crop.Dx() && dh == crop.Dy() && crop == b {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
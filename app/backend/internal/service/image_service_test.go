@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"backend/internal/metclient"
+	"backend/internal/repository"
+)
+
+func TestResizeImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	tests := []struct {
+		w, h  int
+		fit   ImageFit
+		wantW int
+		wantH int
+	}{
+		{200, 0, ImageFitContain, 200, 100},
+		{0, 100, ImageFitContain, 200, 100},
+		{200, 200, ImageFitContain, 200, 100},
+		{200, 200, ImageFitCover, 200, 200},
+		{1000, 1000, ImageFitCover, 400, 400}, // 拡大はしない
+		{1600, 0, ImageFitContain, 800, 400},
+		{0, 0, ImageFitContain, 800, 400},
+	}
+	for _, tt := range tests {
+		got := resizeImage(src, tt.w, tt.h, tt.fit).Bounds()
+		if got.Dx() != tt.wantW || got.Dy() != tt.wantH {
+			t.Errorf("resizeImage(%d, %d, %s) = %dx%d; want %dx%d", tt.w, tt.h, tt.fit, got.Dx(), got.Dy(), tt.wantW, tt.wantH)
+		}
+	}
+}
+
+func TestGetImageResizesAndCaches(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for y := 0; y < 480; y++ {
+		for x := 0; x < 640; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var original bytes.Buffer
+	if err := jpeg.Encode(&original, src, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var imageRequests atomic.Int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/objects/1":
+			fmt.Fprintf(w, `{"objectID": 1, "primaryImage": "%s/original.jpg"}`, srv.URL)
+		case "/objects/2":
+			_, _ = w.Write([]byte(`{"objectID": 2, "primaryImage": ""}`))
+		case "/original.jpg":
+			imageRequests.Add(1)
+			_, _ = w.Write(original.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache, err := repository.NewFileImageCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := metclient.DefaultConfig()
+	cfg.RateLimit = 0
+	s := NewImageService(newTestMetService(srv), metclient.New(cfg), cache, nil)
+
+	opts := ImageOptions{Width: 320, Height: 320, Fit: ImageFitCover}
+	for i := 0; i < 2; i++ {
+		img, err := s.GetImage(context.Background(), 1, opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if img.ContentType != "image/jpeg" || img.ETag == "" {
+			t.Fatalf("unexpected image metadata: %q %q", img.ContentType, img.ETag)
+		}
+		decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+		if err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+		if b := decoded.Bounds(); b.Dx() != 320 || b.Dy() != 320 {
+			t.Fatalf("expected 320x320, got %dx%d", b.Dx(), b.Dy())
+		}
+	}
+	if imageRequests.Load() != 1 {
+		t.Fatalf("expected the second request to be served from cache, got %d downloads", imageRequests.Load())
+	}
+
+	if _, err := s.GetImage(context.Background(), 2, opts); err != ErrArtworkHasNoImage {
+		t.Fatalf("expected ErrArtworkHasNoImage, got %v", err)
+	}
+	if _, err := s.GetImage(context.Background(), 1, ImageOptions{Width: 5000}); err == nil {
+		t.Fatal("expected validation error for too large width")
+	}
+}
+
+// failingImageCache は保存に必ず失敗するキャッシュ（保存時のコンテキストも記録する）
+type failingImageCache struct {
+	putCtxErr error
+}
+
+func (c *failingImageCache) Get(context.Context, string) ([]byte, error) { return nil, nil }
+
+func (c *failingImageCache) Put(ctx context.Context, _ string, _ []byte) error {
+	c.putCtxErr = ctx.Err()
+	return errors.New("disk full")
+}
+
+func TestGetImageIgnoresCacheWriteFailure(t *testing.T) {
+	var original bytes.Buffer
+	if err := jpeg.Encode(&original, image.NewRGBA(image.Rect(0, 0, 64, 64)), nil); err != nil {
+		t.Fatal(err)
+	}
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/objects/1":
+			fmt.Fprintf(w, `{"objectID": 1, "primaryImage": "%s/original.jpg"}`, srv.URL)
+		case "/original.jpg":
+			_, _ = w.Write(original.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache := &failingImageCache{}
+	cfg := metclient.DefaultConfig()
+	cfg.RateLimit = 0
+	s := NewImageService(newTestMetService(srv), metclient.New(cfg), cache, nil)
+
+	// 呼び出し元が切断していても、画像の作成とキャッシュへの保存は取り消されていないコンテキストで行う
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	img, err := s.GetImage(ctx, 1, ImageOptions{Width: 32})
+	if err != nil {
+		t.Fatalf("cache write failure should not fail the request: %v", err)
+	}
+	if len(img.Data) == 0 {
+		t.Fatal("expected image data")
+	}
+	if cache.putCtxErr != nil {
+		t.Fatalf("Put received a canceled context: %v", cache.putCtxErr)
+	}
+}
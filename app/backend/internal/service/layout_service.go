@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log/slog"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
+
+	"backend/internal/domain"
+	"backend/internal/metrics"
+	"backend/internal/render"
+	"backend/internal/repository"
+	"backend/internal/tracing"
+	"backend/internal/validate"
+)
+
+const (
+	layoutMaxPlacements = 20              // 1つの部屋に掛けられる作品数の上限
+	layoutDefaultBG     = "museum-back-1" // 配置を保存していないミュージアムの背景
+	renderCacheVersion  = "v1"            // 合成の方法を変えたら上げて、古いキャッシュを使わないようにする
+	renderConcurrency   = 4               // 作品画像を同時に取得する数
+)
+
+// defaultSlots は配置を保存していないミュージアムで展示作品を並べる枠（CanvasMergeのA・B・C）
+var defaultSlots = []domain.LayoutPlacement{
+	{X: 22, Y: 51, Width: 12, Height: 20},
+	{X: 38, Y: 49, Width: 18, Height: 23},
+	{X: 59, Y: 52, Width: 12, Height: 20},
+}
+
+// LayoutService はミュージアムの部屋の配置と、その合成画像のビジネスロジックを含む
+type LayoutService struct {
+	repo        repository.MuseumLayoutRepository
+	artworkRepo repository.MuseumArtworkRepository
+	museumRepo  repository.MuseumRepository
+	access      *MuseumAccess
+	images      *ImageService
+	cache       repository.ImageCacheRepository // nilの場合は合成画像をキャッシュしない
+	revisions   RevisionRecorder                // nilの場合は変更履歴を記録しない
+	log         *slog.Logger
+	inflight    singleflight.Group
+}
+
+// NewLayoutService は新しいLayoutServiceを作成する
+func NewLayoutService(repo repository.MuseumLayoutRepository, artworkRepo repository.MuseumArtworkRepository, museumRepo repository.MuseumRepository,
+	access *MuseumAccess, images *ImageService, cache repository.ImageCacheRepository, revisions RevisionRecorder, log *slog.Logger) *LayoutService {
+	return &LayoutService{repo: repo, artworkRepo: artworkRepo, museumRepo: museumRepo, access: access, images: images, cache: cache, revisions: revisions, log: log}
+}
+
+// GetLayout はミュージアムの配置を取得する（閲覧できるユーザーのみ）
+func (s *LayoutService) GetLayout(ctx context.Context, museumID int, callerID int) (*domain.MuseumLayout, error) {
+	return s.getLayout(ctx, museumID, callerID, false)
+}
+
+// GetSharedLayout は共有リンク経由でミュージアムの配置を取得する
+// 共有リンクの検証は呼び出し側（ShareService）で済んでいる前提で、公開設定を問わず返す
+func (s *LayoutService) GetSharedLayout(ctx context.Context, museumID int) (*domain.MuseumLayout, error) {
+	return s.getLayout(ctx, museumID, 0, true)
+}
+
+func (s *LayoutService) getLayout(ctx context.Context, museumID int, callerID int, shared bool) (*domain.MuseumLayout, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+
+	if shared {
+		museum, err := s.museumRepo.FindByID(ctx, museumID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get museum: %w", err)
+		}
+		if museum == nil {
+			return nil, errors.New("museum not found")
+		}
+	} else if _, err := s.access.Authorize(ctx, museumID, callerID, domain.PermissionView); err != nil {
+		return nil, err
+	}
+
+	layout, err := s.repo.FindByMuseumID(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get layout: %w", err)
+	}
+	if layout != nil {
+		return layout, nil
+	}
+
+	// 保存していなければ展示作品を先頭から既定の枠に並べる
+	artworks, err := s.artworkRepo.ListByMuseum(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artworks: %w", err)
+	}
+	layout = &domain.MuseumLayout{MuseumID: museumID, Background: layoutDefaultBG, Placements: []domain.LayoutPlacement{}, IsDefault: true}
+	for i, a := range artworks {
+		if i >= len(defaultSlots) {
+			break
+		}
+		slot := defaultSlots[i]
+		slot.ObjectID = a.ObjectID
+		layout.Placements = append(layout.Placements, slot)
+	}
+	return layout, nil
+}
+
+// UpdateLayout はミュージアムの配置を置き換える。変更できるのは所有者と編集者
+// 変更後の配置は update_layout のリビジョンとして記録する
+// 合成画像のキャッシュは配置の内容から作るキーで引くため、変更すると次の合成で作り直される
+func (s *LayoutService) UpdateLayout(ctx context.Context, museumID int, userID int, req domain.MuseumLayoutUpdateRequest) (*domain.MuseumLayout, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+	if err := validateLayout(req); err != nil {
+		return nil, err
+	}
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	layout, err := s.repo.Upsert(ctx, domain.MuseumLayout{MuseumID: museumID, Background: req.Background, Placements: req.Placements})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update layout: %w", err)
+	}
+
+	if s.revisions != nil {
+		s.revisions.Record(ctx, museumID, userID, domain.RevisionUpdateLayout)
+	}
+	return layout, nil
+}
+
+// validateLayout は配置を検証し、失敗したすべてのフィールドをvalidate.Errorsで返す
+func validateLayout(req domain.MuseumLayoutUpdateRequest) error {
+	var errs validate.Errors
+	if err := validate.Struct(req); err != nil {
+		errs = append(errs, err.(validate.Errors)...)
+	}
+	if len(req.Placements) > layoutMaxPlacements {
+		errs = append(errs, validate.FieldError{Field: "placements", Message: fmt.Sprintf("must contain at most %d items", layoutMaxPlacements)})
+	}
+	for i, p := range req.Placements {
+		prefix := "placements[" + strconv.Itoa(i) + "]."
+		if err := validate.Struct(p); err != nil {
+			for _, fe := range err.(validate.Errors) {
+				errs = append(errs, validate.FieldError{Field: prefix + fe.Field, Message: fe.Message})
+			}
+			continue
+		}
+		if p.X+p.Width > 100 {
+			errs = append(errs, validate.FieldError{Field: prefix + "width", Message: "must fit within the background (x + width <= 100)"})
+		}
+		if p.Y+p.Height > 100 {
+			errs = append(errs, validate.FieldError{Field: prefix + "height", Message: "must fit within the background (y + height <= 100)"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Render はミュージアムの部屋の合成画像（PNG）を返す（閲覧できるユーザーのみ）
+func (s *LayoutService) Render(ctx context.Context, museumID int, callerID int) (*Image, error) {
+	layout, err := s.GetLayout(ctx, museumID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(ctx, layout)
+}
+
+// RenderShared は共有リンク経由でミュージアムの部屋の合成画像を返す
+func (s *LayoutService) RenderShared(ctx context.Context, museumID int) (*Image, error) {
+	layout, err := s.GetSharedLayout(ctx, museumID)
+	if err != nil {
+		return nil, err
+	}
+	return s.render(ctx, layout)
+}
+
+// render は配置から合成画像を作る。同じ配置の合成はキャッシュし、同時に要求された場合は1回だけ行う
+func (s *LayoutService) render(ctx context.Context, layout *domain.MuseumLayout) (img *Image, err error) {
+	ctx, span := tracing.Start(ctx, "LayoutService.Render",
+		attribute.Int("museum.id", layout.MuseumID),
+		attribute.Int("layout.placements", len(layout.Placements)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	key, err := renderCacheKey(layout)
+	if err != nil {
+		return nil, err
+	}
+	result := &Image{ContentType: "image/png", ETag: `"` + key[:32] + `"`}
+
+	if s.cache != nil {
+		data, err := s.cache.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		metrics.CacheResult("render", data != nil)
+		span.SetAttributes(attribute.Bool("cache.hit", data != nil))
+		if data != nil {
+			result.Data = data
+			return result, nil
+		}
+	}
+
+	v, err, _ := s.inflight.Do(key, func() (any, error) {
+		// 待っている他のリクエストのため、最初の呼び出し元が切断しても最後まで処理する
+		ctx := context.WithoutCancel(ctx)
+		data, complete, err := s.compose(ctx, layout)
+		if err != nil {
+			return nil, err
+		}
+		// 取得できなかった作品がある場合はキャッシュせず、次の要求で作り直す
+		// キャッシュに保存できなくても合成画像は返せるため、警告のログに留める
+		if s.cache != nil && complete {
+			if err := s.cache.Put(ctx, key, data); err != nil {
+				s.log.Warn("failed to store render in cache",
+					slog.String("error", err.Error()),
+					slog.Int("museumId", layout.MuseumID),
+				)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Data = v.([]byte)
+	return result, nil
+}
+
+// compose は作品画像を取得して合成し、PNGにエンコードする
+// 取得できなかった作品は無地の額で描き、completeをfalseにする
+func (s *LayoutService) compose(ctx context.Context, layout *domain.MuseumLayout) (data []byte, complete bool, err error) {
+	bg, err := render.Background(layout.Background)
+	if err != nil {
+		return nil, false, err
+	}
+
+	placements := make([]render.Placement, len(layout.Placements))
+	failed := make([]bool, len(layout.Placements))
+	sem := make(chan struct{}, renderConcurrency)
+	var wg sync.WaitGroup
+	for i, p := range layout.Placements {
+		placements[i] = render.Placement{X: p.X, Y: p.Y, W: p.Width, H: p.Height}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			w, h := placements[i].InnerSize()
+			artwork, err := s.fetchArtwork(ctx, p.ObjectID, w, h)
+			if err != nil {
+				if !errors.Is(err, ErrArtworkHasNoImage) {
+					failed[i] = true
+				}
+				s.log.Warn("failed to fetch artwork for render",
+					slog.String("error", err.Error()),
+					slog.Int("museumId", layout.MuseumID),
+					slog.Int("objectId", p.ObjectID),
+				)
+				return
+			}
+			placements[i].Artwork = artwork
+		}()
+	}
+	wg.Wait()
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestSpeed}
+	if err := encoder.Encode(&buf, render.Compose(bg, placements)); err != nil {
+		return nil, false, fmt.Errorf("encode png: %w", err)
+	}
+	for _, f := range failed {
+		if f {
+			return buf.Bytes(), false, nil
+		}
+	}
+	return buf.Bytes(), true, nil
+}
+
+// fetchArtwork は枠に収まる大きさに縮小した作品画像を取得する
+func (s *LayoutService) fetchArtwork(ctx context.Context, objectID int, w, h int) (image.Image, error) {
+	img, err := s.images.GetImage(ctx, objectID, ImageOptions{Width: w, Height: h})
+	if err != nil {
+		return nil, err
+	}
+	return jpeg.Decode(bytes.NewReader(img.Data))
+}
+
+// renderCacheKey は合成結果を決める内容（背景と配置）からキャッシュのキーを作る
+func renderCacheKey(layout *domain.MuseumLayout) (string, error) {
+	content, err := json.Marshal(struct {
+		Version    string                   `json:"v"`
+		Background string                   `json:"background"`
+		Placements []domain.LayoutPlacement `json:"placements"`
+	}{renderCacheVersion, layout.Background, layout.Placements})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
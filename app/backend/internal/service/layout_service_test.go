@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+	"backend/internal/validate"
+)
+
+func TestValidateLayout(t *testing.T) {
+	valid := domain.MuseumLayoutUpdateRequest{
+		Background: "museum-back-1",
+		Placements: []domain.LayoutPlacement{{ObjectID: 1, X: 22, Y: 51, Width: 12, Height: 20}},
+	}
+	if err := validateLayout(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	invalid := domain.MuseumLayoutUpdateRequest{
+		Background: "garden",
+		Placements: []domain.LayoutPlacement{
+			{ObjectID: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{ObjectID: 2, X: 95, Y: 0, Width: 10, Height: 10},
+		},
+	}
+	var errs validate.Errors
+	if !errors.As(validateLayout(invalid), &errs) {
+		t.Fatalf("expected validation errors")
+	}
+	want := map[string]bool{"background": true, "placements[0].objectId": true, "placements[1].width": true}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %+v", len(want), errs)
+	}
+	for _, fe := range errs {
+		if !want[fe.Field] {
+			t.Fatalf("unexpected error %+v", fe)
+		}
+	}
+}
+
+func TestRenderCacheKeyChangesWithLayout(t *testing.T) {
+	layout := &domain.MuseumLayout{MuseumID: 1, Background: "museum-back-1",
+		Placements: []domain.LayoutPlacement{{ObjectID: 1, X: 22, Y: 51, Width: 12, Height: 20}}}
+	before, _ := renderCacheKey(layout)
+
+	layout.Placements[0].X = 30
+	after, _ := renderCacheKey(layout)
+	if before == after {
+		t.Fatal("expected the cache key to change with the layout")
+	}
+}
+
+// memoryLayoutRepo はUpsertだけを実装したMuseumLayoutRepository
+type memoryLayoutRepo struct {
+	repository.MuseumLayoutRepository
+	saved []domain.MuseumLayout
+}
+
+func (r *memoryLayoutRepo) Upsert(_ context.Context, layout domain.MuseumLayout) (*domain.MuseumLayout, error) {
+	r.saved = append(r.saved, layout)
+	return &layout, nil
+}
+
+// recordedRevisions は記録されたリビジョンの操作を残すRevisionRecorder
+type recordedRevisions []domain.RevisionAction
+
+func (r *recordedRevisions) Record(_ context.Context, _ int, _ int, action domain.RevisionAction) {
+	*r = append(*r, action)
+}
+
+func TestUpdateLayoutRecordsRevision(t *testing.T) {
+	repo := &memoryLayoutRepo{}
+	var revisions recordedRevisions
+	s := NewLayoutService(repo, nil, nil, newTestMuseumAccess(domain.VisibilityPublic), nil, nil, &revisions, nil)
+	req := domain.MuseumLayoutUpdateRequest{
+		Background: "museum-back-1",
+		Placements: []domain.LayoutPlacement{{ObjectID: 1, X: 22, Y: 51, Width: 12, Height: 20}},
+	}
+
+	if _, err := s.UpdateLayout(context.Background(), 10, accessEditor, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repo.saved) != 1 || len(revisions) != 1 || revisions[0] != domain.RevisionUpdateLayout {
+		t.Fatalf("expected one saved layout and an update_layout revision, got %d layouts, revisions %v", len(repo.saved), revisions)
+	}
+
+	// 保存できなかった変更は記録しない
+	if _, err := s.UpdateLayout(context.Background(), 10, accessViewer, req); err == nil {
+		t.Fatal("expected viewer to be denied")
+	}
+	if len(repo.saved) != 1 || len(revisions) != 1 {
+		t.Errorf("denied update was saved or recorded: %d layouts, revisions %v", len(repo.saved), revisions)
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// MemberService はミュージアムの共同キュレーターと招待のビジネスロジックを含む
+type MemberService struct {
+	repo   repository.MuseumMemberRepository
+	access *MuseumAccess
+}
+
+// NewMemberService は新しいMemberServiceを作成する
+func NewMemberService(repo repository.MuseumMemberRepository, access *MuseumAccess) *MemberService {
+	return &MemberService{repo: repo, access: access}
+}
+
+// ListMembers はミュージアムのメンバー一覧を取得する。取得できるのはメンバーのみ
+func (s *MemberService) ListMembers(ctx context.Context, museumID int, userID int) ([]domain.MuseumMember, error) {
+	if err := s.ensureMember(ctx, museumID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+	return members, nil
+}
+
+// UpdateRole はメンバーの役割を変更する（owner役割のみ）。作成者の役割は変更できない
+func (s *MemberService) UpdateRole(ctx context.Context, museumID int, targetUserID int, userID int, role domain.MuseumRole) error {
+	if !role.IsValid() {
+		return errors.New("invalid role")
+	}
+	museum, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionManage)
+	if err != nil {
+		return err
+	}
+	if museum.IsOwnedBy(targetUserID) {
+		return errors.New("cannot change the creator's role")
+	}
+
+	if err := s.repo.UpdateRole(ctx, museumID, targetUserID, role); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("member not found")
+		}
+		return fmt.Errorf("failed to update member role: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember はメンバーを外す。owner役割のユーザーは誰でも外せ、メンバー本人は自分で抜けられる
+// 作成者は外せない
+func (s *MemberService) RemoveMember(ctx context.Context, museumID int, targetUserID int, userID int) error {
+	perm := domain.PermissionManage
+	if targetUserID == userID {
+		perm = domain.PermissionView
+	}
+	museum, err := s.access.Authorize(ctx, museumID, userID, perm)
+	if err != nil {
+		return err
+	}
+	if museum.IsOwnedBy(targetUserID) {
+		return errors.New("cannot remove the creator")
+	}
+
+	if err := s.repo.RemoveMember(ctx, museumID, targetUserID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("member not found")
+		}
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+	return nil
+}
+
+// Invite はユーザーIDまたはメールアドレスで共同キュレーターを招待する（owner役割のみ）
+// 未登録のメールアドレスも招待でき、そのアドレスで登録したユーザーが承諾できる
+func (s *MemberService) Invite(ctx context.Context, museumID int, userID int, req domain.MuseumInvitationCreateRequest) (*domain.MuseumInvitation, error) {
+	if !req.Role.IsValid() {
+		return nil, errors.New("invalid role")
+	}
+	email := strings.TrimSpace(req.Email)
+	if (req.UserID == nil) == (email == "") {
+		return nil, errors.New("either userId or email is required")
+	}
+	if email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, errors.New("invalid email")
+		}
+	}
+
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionManage); err != nil {
+		return nil, err
+	}
+
+	inv := domain.MuseumInvitation{
+		MuseumID:     museumID,
+		InvitedBy:    userID,
+		InviteeEmail: email,
+		Role:         req.Role,
+	}
+
+	inviteeID := 0
+	if req.UserID != nil {
+		inviteeID = *req.UserID
+		if inviteeID <= 0 {
+			return nil, errors.New("invalid user ID")
+		}
+	}
+	foundID, _, err := s.repo.FindUser(ctx, inviteeID, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if req.UserID != nil && foundID == 0 {
+		return nil, errors.New("user not found")
+	}
+	if foundID > 0 {
+		inv.InviteeUserID = &foundID
+
+		role, err := s.repo.GetRole(ctx, museumID, foundID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get museum role: %w", err)
+		}
+		if role != "" {
+			return nil, errors.New("user is already a member")
+		}
+	}
+
+	created, err := s.repo.InsertInvitation(ctx, inv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+	return created, nil
+}
+
+// ListMuseumInvitations はミュージアムの保留中の招待を取得する（owner役割のみ）
+func (s *MemberService) ListMuseumInvitations(ctx context.Context, museumID int, userID int) ([]domain.MuseumInvitation, error) {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionManage); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.repo.ListInvitationsByMuseum(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation は保留中の招待を取り消す（owner役割のみ）
+func (s *MemberService) RevokeInvitation(ctx context.Context, museumID int, invitationID int, userID int) error {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionManage); err != nil {
+		return err
+	}
+
+	inv, err := s.findPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if inv.MuseumID != museumID {
+		return errors.New("invitation not found")
+	}
+
+	return s.setInvitationStatus(ctx, invitationID, domain.InvitationRevoked)
+}
+
+// ListMyInvitations は自分宛ての保留中の招待を取得する
+func (s *MemberService) ListMyInvitations(ctx context.Context, userID int) ([]domain.MuseumInvitation, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	invitations, err := s.repo.ListInvitationsForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation は自分宛ての招待を承諾し、メンバーになる
+func (s *MemberService) AcceptInvitation(ctx context.Context, invitationID int, userID int) error {
+	if _, err := s.myPendingInvitation(ctx, invitationID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.AcceptInvitation(ctx, invitationID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("invitation not found")
+		}
+		return fmt.Errorf("failed to accept invitation: %w", err)
+	}
+	return nil
+}
+
+// DeclineInvitation は自分宛ての招待を辞退する
+func (s *MemberService) DeclineInvitation(ctx context.Context, invitationID int, userID int) error {
+	if _, err := s.myPendingInvitation(ctx, invitationID, userID); err != nil {
+		return err
+	}
+
+	return s.setInvitationStatus(ctx, invitationID, domain.InvitationDeclined)
+}
+
+// ensureMember は呼び出しユーザーがミュージアムのメンバーか確認する
+func (s *MemberService) ensureMember(ctx context.Context, museumID int, userID int) error {
+	museum, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionView)
+	if err != nil {
+		return err
+	}
+
+	role, err := s.access.Role(ctx, museum, userID)
+	if err != nil {
+		return err
+	}
+	if role == "" {
+		return errors.New("permission denied")
+	}
+	return nil
+}
+
+// myPendingInvitation は自分宛て（ユーザーIDまたは登録メールアドレス宛て）の保留中の招待を取得する
+func (s *MemberService) myPendingInvitation(ctx context.Context, invitationID int, userID int) (*domain.MuseumInvitation, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	inv, err := s.findPendingInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.InviteeUserID != nil {
+		if *inv.InviteeUserID != userID {
+			return nil, errors.New("invitation not found")
+		}
+		return inv, nil
+	}
+
+	_, email, err := s.repo.FindUser(ctx, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if email == "" || !strings.EqualFold(email, inv.InviteeEmail) {
+		return nil, errors.New("invitation not found")
+	}
+	return inv, nil
+}
+
+// findPendingInvitation は保留中の招待を取得する
+func (s *MemberService) findPendingInvitation(ctx context.Context, invitationID int) (*domain.MuseumInvitation, error) {
+	if invitationID <= 0 {
+		return nil, errors.New("invalid invitation ID")
+	}
+
+	inv, err := s.repo.FindInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invitation: %w", err)
+	}
+	if inv == nil || inv.Status != domain.InvitationPending {
+		return nil, errors.New("invitation not found")
+	}
+	return inv, nil
+}
+
+// setInvitationStatus は保留中の招待の状態を変更する
+func (s *MemberService) setInvitationStatus(ctx context.Context, invitationID int, status domain.InvitationStatus) error {
+	if err := s.repo.UpdateInvitationStatus(ctx, invitationID, status); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("invitation not found")
+		}
+		return fmt.Errorf("failed to update invitation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// memoryMemberRepo はメモリ上でメンバーと招待を保持するMuseumMemberRepository
+type memoryMemberRepo struct {
+	repository.MuseumMemberRepository
+	roles       map[int]domain.MuseumRole // ユーザーID → 役割
+	emails      map[int]string            // 登録済みユーザーのメールアドレス
+	invitations map[int]*domain.MuseumInvitation
+}
+
+const accessInvitee = 5 // メールアドレスで招待される未参加のユーザー
+
+func newMemoryMemberRepo() *memoryMemberRepo {
+	return &memoryMemberRepo{
+		roles: map[int]domain.MuseumRole{
+			accessEditor: domain.RoleEditor,
+			accessViewer: domain.RoleViewer,
+		},
+		emails: map[int]string{
+			accessOwner:    "owner@example.com",
+			accessEditor:   "editor@example.com",
+			accessViewer:   "viewer@example.com",
+			accessStranger: "stranger@example.com",
+			accessInvitee:  "invitee@example.com",
+		},
+		invitations: map[int]*domain.MuseumInvitation{},
+	}
+}
+
+func (r *memoryMemberRepo) GetRole(_ context.Context, _, userID int) (domain.MuseumRole, error) {
+	return r.roles[userID], nil
+}
+
+func (r *memoryMemberRepo) UpdateRole(_ context.Context, _, userID int, role domain.MuseumRole) error {
+	if r.roles[userID] == "" {
+		return sql.ErrNoRows
+	}
+	r.roles[userID] = role
+	return nil
+}
+
+func (r *memoryMemberRepo) RemoveMember(_ context.Context, _, userID int) error {
+	if r.roles[userID] == "" {
+		return sql.ErrNoRows
+	}
+	delete(r.roles, userID)
+	return nil
+}
+
+func (r *memoryMemberRepo) FindUser(_ context.Context, userID int, email string) (int, string, error) {
+	for id, e := range r.emails {
+		if (userID > 0 && id == userID) || (userID <= 0 && strings.EqualFold(e, email)) {
+			return id, e, nil
+		}
+	}
+	return 0, "", nil
+}
+
+func (r *memoryMemberRepo) InsertInvitation(_ context.Context, inv domain.MuseumInvitation) (*domain.MuseumInvitation, error) {
+	inv.ID = len(r.invitations) + 1
+	inv.Status = domain.InvitationPending
+	r.invitations[inv.ID] = &inv
+	return &inv, nil
+}
+
+func (r *memoryMemberRepo) FindInvitation(_ context.Context, id int) (*domain.MuseumInvitation, error) {
+	return r.invitations[id], nil
+}
+
+func (r *memoryMemberRepo) AcceptInvitation(_ context.Context, id, userID int) error {
+	inv := r.invitations[id]
+	inv.Status = domain.InvitationAccepted
+	r.roles[userID] = inv.Role
+	return nil
+}
+
+func newTestMemberService() (*MemberService, *memoryMemberRepo) {
+	repo := newMemoryMemberRepo()
+	museums := accessMuseumRepo{museums: map[int]*domain.Museum{
+		10: {ID: 10, UserID: accessOwner, Visibility: domain.VisibilityPublic},
+	}}
+	return NewMemberService(repo, NewMuseumAccess(museums, repo)), repo
+}
+
+func TestInviteRequiresEitherUserIDOrEmail(t *testing.T) {
+	s, _ := newTestMemberService()
+	invitee := accessInvitee
+	for name, req := range map[string]domain.MuseumInvitationCreateRequest{
+		"both":    {UserID: &invitee, Email: "invitee@example.com", Role: domain.RoleEditor},
+		"neither": {Email: "  ", Role: domain.RoleEditor},
+	} {
+		if _, err := s.Invite(context.Background(), 10, accessOwner, req); err == nil || err.Error() != "either userId or email is required" {
+			t.Errorf("%s: expected either userId or email is required, got %v", name, err)
+		}
+	}
+}
+
+func TestInviteByEmail(t *testing.T) {
+	s, _ := newTestMemberService()
+
+	// 登録済みのアドレスならユーザーにひも付け、未登録のアドレスはそのまま招待する
+	inv, err := s.Invite(context.Background(), 10, accessOwner, domain.MuseumInvitationCreateRequest{Email: "Invitee@Example.com", Role: domain.RoleEditor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.InviteeUserID == nil || *inv.InviteeUserID != accessInvitee {
+		t.Errorf("registered email: invitee = %v, want %d", inv.InviteeUserID, accessInvitee)
+	}
+
+	inv, err = s.Invite(context.Background(), 10, accessOwner, domain.MuseumInvitationCreateRequest{Email: "new@example.com", Role: domain.RoleViewer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inv.InviteeUserID != nil || inv.InviteeEmail != "new@example.com" {
+		t.Errorf("unregistered email: got %+v", inv)
+	}
+}
+
+func TestInviteRejectsExistingMember(t *testing.T) {
+	s, _ := newTestMemberService()
+	editor := accessEditor
+	for name, req := range map[string]domain.MuseumInvitationCreateRequest{
+		"user ID": {UserID: &editor, Role: domain.RoleViewer},
+		"email":   {Email: "editor@example.com", Role: domain.RoleViewer},
+	} {
+		if _, err := s.Invite(context.Background(), 10, accessOwner, req); err == nil || err.Error() != "user is already a member" {
+			t.Errorf("%s: expected user is already a member, got %v", name, err)
+		}
+	}
+}
+
+func TestUpdateRoleOfCreator(t *testing.T) {
+	s, repo := newTestMemberService()
+	if err := s.UpdateRole(context.Background(), 10, accessOwner, accessOwner, domain.RoleViewer); err == nil || err.Error() != "cannot change the creator's role" {
+		t.Errorf("expected cannot change the creator's role, got %v", err)
+	}
+
+	if err := s.UpdateRole(context.Background(), 10, accessViewer, accessOwner, domain.RoleEditor); err != nil {
+		t.Fatal(err)
+	}
+	if repo.roles[accessViewer] != domain.RoleEditor {
+		t.Errorf("role = %q, want %q", repo.roles[accessViewer], domain.RoleEditor)
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	s, repo := newTestMemberService()
+
+	// 閲覧者は他のメンバーを外せないが、自分は抜けられる
+	if err := s.RemoveMember(context.Background(), 10, accessEditor, accessViewer); err == nil || err.Error() != "permission denied" {
+		t.Errorf("viewer removing editor: expected permission denied, got %v", err)
+	}
+	if err := s.RemoveMember(context.Background(), 10, accessViewer, accessViewer); err != nil {
+		t.Fatalf("viewer leaving: %v", err)
+	}
+	if _, ok := repo.roles[accessViewer]; ok {
+		t.Error("viewer is still a member")
+	}
+
+	if err := s.RemoveMember(context.Background(), 10, accessOwner, accessOwner); err == nil || err.Error() != "cannot remove the creator" {
+		t.Errorf("creator leaving: expected cannot remove the creator, got %v", err)
+	}
+}
+
+func TestAcceptInvitationByEmail(t *testing.T) {
+	s, repo := newTestMemberService()
+	inv, err := s.Invite(context.Background(), 10, accessOwner, domain.MuseumInvitationCreateRequest{Email: "later@example.com", Role: domain.RoleEditor})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 招待後に同じアドレス（大文字小文字は区別しない）で登録したユーザーだけが承諾できる
+	const registered = 6
+	repo.emails[registered] = "Later@Example.com"
+
+	if err := s.AcceptInvitation(context.Background(), inv.ID, accessStranger); err == nil || err.Error() != "invitation not found" {
+		t.Errorf("other user: expected invitation not found, got %v", err)
+	}
+	if err := s.AcceptInvitation(context.Background(), inv.ID, registered); err != nil {
+		t.Fatalf("matching email: %v", err)
+	}
+	if repo.roles[registered] != domain.RoleEditor {
+		t.Errorf("role = %q, want %q", repo.roles[registered], domain.RoleEditor)
+	}
+
+	if err := s.AcceptInvitation(context.Background(), inv.ID, registered); err == nil || err.Error() != "invitation not found" {
+		t.Errorf("accepting twice: expected invitation not found, got %v", err)
+	}
+}
@@ -1,17 +1,44 @@
 package service
 
 import (
+    "context"
     "encoding/json"
+    "errors"
     "fmt"
     "net/http"
+    "strconv"
+    "sync"
+
+    "go.opentelemetry.io/otel/attribute"
+    "golang.org/x/sync/singleflight"
+
+    "backend/internal/metclient"
+    "backend/internal/metrics"
+    "backend/internal/tracing"
 )
 
+const (
+    metBatchConcurrency = 8 // 一括取得でMET APIへ同時に送るリクエスト数
+)
+
+// MetObjectsBatchMax は一括取得で一度に指定できる作品IDの最大数
+const MetObjectsBatchMax = 100
+
+// ErrMetObjectNotFound はMET APIに作品が存在しない場合に返される
+var ErrMetObjectNotFound = errors.New("MET object not found")
+
 type MetService struct {
-    client *http.Client
+    client   *metclient.Client
+    baseURL  string
+    inflight singleflight.Group // 作品IDごとに実行中の取得をまとめる
 }
 
-func NewMetService() *MetService {
-    return &MetService{client: &http.Client{}}
+// NewMetService はMET APIの共有クライアントを使うMetServiceを作成する
+func NewMetService(client *metclient.Client) *MetService {
+    return &MetService{
+        client:  client,
+        baseURL: "https://collectionapi.metmuseum.org/public/collection/v1",
+    }
 }
 
 type MetObject struct {
@@ -21,6 +48,7 @@ type MetObject struct {
     Department        string `json:"department"`         // 部門
     ObjectDate        string `json:"objectDate"`         // 制作年代
     Medium            string `json:"medium"`             // 材質・技法
+    CreditLine        string `json:"creditLine"`         // 所蔵・取得の経緯（クレジットライン、例: "Bequest of ..."）
     IsPublicDomain    bool   `json:"isPublicDomain"`     // パブリックドメインか
     PrimaryImage      string `json:"primaryImage"`       // 高解像度画像URL
     PrimaryImageSmall string `json:"primaryImageSmall"`  // サムネイル画像URL
@@ -31,22 +59,115 @@ type MetObject struct {
 }
 
 // GetObjectByID fetches a single artwork object from the MET API
-func (s *MetService) GetObjectByID(id int) (*MetObject, error) {
-    url := fmt.Sprintf("https://collectionapi.metmuseum.org/public/collection/v1/objects/%d", id)
-    resp, err := s.client.Get(url)
+// 同じ作品IDの取得が同時に走った場合はMET APIへのリクエストを1件にまとめ、結果を共有する
+func (s *MetService) GetObjectByID(ctx context.Context, id int) (obj *MetObject, err error) {
+    ctx, span := tracing.Start(ctx, "MetService.GetObjectByID", attribute.Int("met.object_id", id))
+    defer func() { tracing.End(span, err) }()
+
+    // 最初の呼び出し元がリクエストを中断しても相乗りした呼び出し元が失敗しないよう、キャンセルを切り離す
+    // （試行ごとのタイムアウトはmetclientが持つ）
+    leader := false
+    v, err, _ := s.inflight.Do(strconv.Itoa(id), func() (any, error) {
+        leader = true
+        return s.fetchObject(context.WithoutCancel(ctx), id)
+    })
+    span.SetAttributes(attribute.Bool("met.coalesced", !leader))
+    if !leader {
+        metrics.METCoalesced.Inc()
+    }
     if err != nil {
         return nil, err
     }
+    fetched := v.(MetObject)
+    return &fetched, nil
+}
+
+// fetchObject はMET APIから作品情報を取得する
+func (s *MetService) fetchObject(ctx context.Context, id int) (MetObject, error) {
+    resp, err := s.client.Get(ctx, fmt.Sprintf("%s/objects/%d", s.baseURL, id))
+    if err != nil {
+        return MetObject{}, err
+    }
     defer resp.Body.Close()
 
+    if resp.StatusCode == http.StatusNotFound {
+        return MetObject{}, ErrMetObjectNotFound
+    }
     if resp.StatusCode != http.StatusOK {
-        return nil, fmt.Errorf("MET API returned %d", resp.StatusCode)
+        return MetObject{}, fmt.Errorf("MET API returned %d", resp.StatusCode)
     }
 
-    var obj MetObject
-    if err := json.NewDecoder(resp.Body).Decode(&obj); err != nil {
-        return nil, err
+    var decoded MetObject
+    if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+        metrics.METErrors.WithLabelValues("objects", "decode").Inc()
+        return MetObject{}, err
     }
 
-    return &obj, nil
-}
\ No newline at end of file
+    return decoded, nil
+}
+
+// MetObjectError は一括取得で取得できなかった作品とその理由
+type MetObjectError struct {
+    ObjectID int    `json:"objectID"`
+    Error    string `json:"error"`
+}
+
+// MetObjectBatch は一括取得の結果（objectsは指定した順、取得できなかった作品はerrorsに入る）
+type MetObjectBatch struct {
+    Objects []MetObject      `json:"objects"`
+    Errors  []MetObjectError `json:"errors"`
+}
+
+// GetObjectsByIDs は複数の作品情報を同時実行数を抑えて取得する
+// 一部の作品が取得できなくても成功として返し、すべてサーキットブレーカーで失敗した場合だけエラーにする
+func (s *MetService) GetObjectsByIDs(ctx context.Context, ids []int) (batch *MetObjectBatch, err error) {
+    ctx, span := tracing.Start(ctx, "MetService.GetObjectsByIDs", attribute.Int("met.object_count", len(ids)))
+    defer func() { tracing.End(span, err) }()
+
+    objects := make([]*MetObject, len(ids))
+    errs := make([]error, len(ids))
+    sem := make(chan struct{}, metBatchConcurrency)
+    var wg sync.WaitGroup
+    for i, id := range ids {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            sem <- struct{}{}
+            defer func() { <-sem }()
+            objects[i], errs[i] = s.GetObjectByID(ctx, id)
+        }()
+    }
+    wg.Wait()
+
+    batch = &MetObjectBatch{Objects: []MetObject{}, Errors: []MetObjectError{}}
+    circuitOpen := 0
+    for i, id := range ids {
+        if errs[i] != nil {
+            if errors.Is(errs[i], metclient.ErrCircuitOpen) {
+                circuitOpen++
+            }
+            batch.Errors = append(batch.Errors, MetObjectError{ObjectID: id, Error: errs[i].Error()})
+            continue
+        }
+        batch.Objects = append(batch.Objects, *objects[i])
+    }
+    if len(ids) > 0 && circuitOpen == len(ids) {
+        return nil, metclient.ErrCircuitOpen
+    }
+    return batch, nil
+}
+
+// Ping はMET APIに到達できるか確認する（readyzの確認用）
+// 応答の小さい部門一覧のエンドポイントを呼び、200以外は失敗とする
+func (s *MetService) Ping(ctx context.Context) error {
+    resp, err := s.client.Get(ctx, s.baseURL+"/departments")
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("MET API returned %d", resp.StatusCode)
+    }
+    return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"backend/internal/metclient"
+)
+
+// newTestMetService はsrvをMET APIとして使うMetServiceを作成する
+func newTestMetService(srv *httptest.Server) *MetService {
+	cfg := metclient.DefaultConfig()
+	cfg.RateLimit = 0
+	cfg.MaxAttempts = 1
+	s := NewMetService(metclient.New(cfg))
+	s.baseURL = srv.URL
+	return s
+}
+
+func TestGetObjectByIDCoalescesConcurrentLookups(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"objectID": 1, "title": "Wheat Field with Cypresses"}`))
+	}))
+	defer srv.Close()
+	s := newTestMetService(srv)
+
+	const callers = 10
+	var wg sync.WaitGroup
+	titles := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj, err := s.GetObjectByID(context.Background(), 1)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			titles[i] = obj.Title
+		}()
+	}
+	// すべての呼び出しが実行中の取得に相乗りするまで待ってから応答する
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single MET request, got %d", calls.Load())
+	}
+	for i, title := range titles {
+		if title != "Wheat Field with Cypresses" {
+			t.Fatalf("caller %d got %q", i, title)
+		}
+	}
+}
+
+func TestGetObjectsByIDsKeepsOrderAndReportsFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/objects/")
+		if id == "404" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"objectID": %s}`, id)
+	}))
+	defer srv.Close()
+	s := newTestMetService(srv)
+
+	batch, err := s.GetObjectsByIDs(context.Background(), []int{3, 404, 1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int
+	for _, obj := range batch.Objects {
+		got = append(got, obj.ObjectID)
+	}
+	if fmt.Sprint(got) != "[3 1 2]" {
+		t.Fatalf("expected objects in requested order, got %v", got)
+	}
+	if len(batch.Errors) != 1 || batch.Errors[0].ObjectID != 404 || batch.Errors[0].Error != ErrMetObjectNotFound.Error() {
+		t.Fatalf("expected not found error for 404, got %+v", batch.Errors)
+	}
+}
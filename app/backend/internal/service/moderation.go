@@ -0,0 +1,52 @@
+package service
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ModerationResult はコメント審査の結果
+type ModerationResult struct {
+	Allowed bool
+	Reason  string // 却下理由（利用者には返さない）
+}
+
+// CommentModerator は投稿・編集されたコメント本文を審査するフィルター
+// 外部の審査APIなどに差し替えられるようにインターフェースにしている
+type CommentModerator interface {
+	Moderate(text string) (ModerationResult, error)
+}
+
+// WordListModerator は禁止語リストに一致する語を含むコメントを却下するローカル実装
+type WordListModerator struct {
+	words []string
+}
+
+// NewWordListModerator は禁止語リストからWordListModeratorを作成する
+func NewWordListModerator(words []string) *WordListModerator {
+	normalized := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = normalizeForModeration(w); w != "" {
+			normalized = append(normalized, w)
+		}
+	}
+	return &WordListModerator{words: normalized}
+}
+
+// Moderate は本文に禁止語が含まれていないか確認する
+// 全角・半角や大文字・小文字の違いは同一視する
+func (m *WordListModerator) Moderate(text string) (ModerationResult, error) {
+	normalized := normalizeForModeration(text)
+	for _, w := range m.words {
+		if strings.Contains(normalized, w) {
+			return ModerationResult{Allowed: false, Reason: "contains banned word: " + w}, nil
+		}
+	}
+	return ModerationResult{Allowed: true}, nil
+}
+
+// normalizeForModeration はNFKC正規化と小文字化で表記揺れを吸収する
+func normalizeForModeration(s string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(s)))
+}
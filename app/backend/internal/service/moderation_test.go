@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestWordListModerator_Moderate(t *testing.T) {
+	m := NewWordListModerator([]string{"spam", "バカ", " "})
+
+	cases := []struct {
+		text    string
+		allowed bool
+	}{
+		{"素敵な展示ですね", true},
+		{"Buy SPAM now", false},
+		{"ｓｐａｍ", false}, // 全角
+		{"ﾊﾞｶ", false},  // 半角カナ
+		{"spa m", true},
+		{"", true},
+	}
+	for _, c := range cases {
+		res, err := m.Moderate(c.text)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Allowed != c.allowed {
+			t.Fatalf("Moderate(%q) allowed=%v, want %v", c.text, res.Allowed, c.allowed)
+		}
+	}
+}
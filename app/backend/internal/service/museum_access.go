@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// MuseumAccess はミュージアムに対するユーザーの権限を判定する
+// 作成者（museums.user_id）と museum_members の役割をもとに判定し、各サービスから共通で使う
+type MuseumAccess struct {
+	museumRepo repository.MuseumRepository
+	memberRepo repository.MuseumMemberRepository
+}
+
+// NewMuseumAccess は新しいMuseumAccessを作成する
+func NewMuseumAccess(museumRepo repository.MuseumRepository, memberRepo repository.MuseumMemberRepository) *MuseumAccess {
+	return &MuseumAccess{museumRepo: museumRepo, memberRepo: memberRepo}
+}
+
+// Role はユーザーのミュージアムに対する役割を返す。メンバーでなければ空文字
+func (a *MuseumAccess) Role(ctx context.Context, museum *domain.Museum, userID int) (domain.MuseumRole, error) {
+	if userID <= 0 {
+		return "", nil
+	}
+	if museum.IsOwnedBy(userID) {
+		return domain.RoleOwner, nil
+	}
+
+	role, err := a.memberRepo.GetRole(ctx, museum.ID, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get museum role: %w", err)
+	}
+	return role, nil
+}
+
+// Authorize はミュージアムを取得し、ユーザーが指定の操作を行えるか確認する
+// 閲覧すらできない非公開ミュージアムは存在しないものとして扱い（museum not found）、
+// 閲覧はできるが権限が足りない場合は permission denied を返す
+func (a *MuseumAccess) Authorize(ctx context.Context, museumID int, userID int, perm domain.MuseumPermission) (*domain.Museum, error) {
+	if museumID <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+
+	museum, err := a.museumRepo.FindByID(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get museum: %w", err)
+	}
+	if museum == nil {
+		return nil, errors.New("museum not found")
+	}
+
+	role, err := a.Role(ctx, museum, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	canView := museum.Visibility != domain.VisibilityPrivate || role.Can(domain.PermissionView)
+	if !canView {
+		return nil, errors.New("museum not found")
+	}
+	if perm == domain.PermissionView {
+		return museum, nil
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if !role.Can(perm) {
+		return nil, errors.New("permission denied")
+	}
+	return museum, nil
+}
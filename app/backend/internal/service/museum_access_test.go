@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// accessMuseumRepo はFindByIDだけを実装したMuseumRepository
+type accessMuseumRepo struct {
+	repository.MuseumRepository
+	museums map[int]*domain.Museum
+}
+
+func (r accessMuseumRepo) FindByID(_ context.Context, id int) (*domain.Museum, error) {
+	return r.museums[id], nil
+}
+
+// accessMemberRepo はGetRoleだけを実装したMuseumMemberRepository
+type accessMemberRepo struct {
+	repository.MuseumMemberRepository
+	roles map[int]domain.MuseumRole // ユーザーID → 役割
+}
+
+func (r accessMemberRepo) GetRole(_ context.Context, _, userID int) (domain.MuseumRole, error) {
+	return r.roles[userID], nil
+}
+
+const (
+	accessOwner     = 1
+	accessEditor    = 2
+	accessViewer    = 3
+	accessStranger  = 4
+	accessAnonymous = 0
+)
+
+func newTestMuseumAccess(visibility domain.VisibilityType) *MuseumAccess {
+	museums := accessMuseumRepo{museums: map[int]*domain.Museum{
+		10: {ID: 10, UserID: accessOwner, Visibility: visibility},
+	}}
+	members := accessMemberRepo{roles: map[int]domain.MuseumRole{
+		accessEditor: domain.RoleEditor,
+		accessViewer: domain.RoleViewer,
+	}}
+	return NewMuseumAccess(museums, members)
+}
+
+func TestAuthorize(t *testing.T) {
+	const (
+		ok       = ""
+		notFound = "museum not found"
+		denied   = "permission denied"
+		noUser   = "invalid user ID"
+	)
+	users := []int{accessOwner, accessEditor, accessViewer, accessStranger, accessAnonymous}
+	perms := []domain.MuseumPermission{domain.PermissionView, domain.PermissionEdit, domain.PermissionManage}
+
+	// want[visibility][user][perm]
+	want := map[domain.VisibilityType]map[int][3]string{
+		domain.VisibilityPublic: {
+			accessOwner:     {ok, ok, ok},
+			accessEditor:    {ok, ok, denied},
+			accessViewer:    {ok, denied, denied},
+			accessStranger:  {ok, denied, denied},
+			accessAnonymous: {ok, noUser, noUser},
+		},
+		// 限定公開はリンクを知っていれば誰でも閲覧できる（一覧・検索に出ないだけ）
+		domain.VisibilityUnlisted: {
+			accessOwner:     {ok, ok, ok},
+			accessEditor:    {ok, ok, denied},
+			accessViewer:    {ok, denied, denied},
+			accessStranger:  {ok, denied, denied},
+			accessAnonymous: {ok, noUser, noUser},
+		},
+		// 非公開は閲覧できないユーザーには存在しないものとして扱う
+		domain.VisibilityPrivate: {
+			accessOwner:     {ok, ok, ok},
+			accessEditor:    {ok, ok, denied},
+			accessViewer:    {ok, denied, denied},
+			accessStranger:  {notFound, notFound, notFound},
+			accessAnonymous: {notFound, notFound, notFound},
+		},
+	}
+
+	for visibility, byUser := range want {
+		access := newTestMuseumAccess(visibility)
+		for _, user := range users {
+			for _, perm := range perms {
+				museum, err := access.Authorize(context.Background(), 10, user, perm)
+				got := ok
+				if err != nil {
+					got = err.Error()
+				}
+				if got != byUser[user][perm] {
+					t.Errorf("%s museum, user %d, permission %d: got %q, want %q", visibility, user, perm, got, byUser[user][perm])
+				}
+				if err == nil && (museum == nil || museum.ID != 10) {
+					t.Errorf("%s museum, user %d, permission %d: unexpected museum %+v", visibility, user, perm, museum)
+				}
+			}
+		}
+	}
+}
+
+func TestAuthorizeMissingMuseum(t *testing.T) {
+	access := newTestMuseumAccess(domain.VisibilityPublic)
+	if _, err := access.Authorize(context.Background(), 99, accessOwner, domain.PermissionView); err == nil || err.Error() != "museum not found" {
+		t.Errorf("expected museum not found, got %v", err)
+	}
+	if _, err := access.Authorize(context.Background(), 0, accessOwner, domain.PermissionView); err == nil || err.Error() != "invalid museum ID" {
+		t.Errorf("expected invalid museum ID, got %v", err)
+	}
+}
@@ -1,28 +1,58 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"backend/internal/domain"
+	"backend/internal/metrics"
 	"backend/internal/repository"
+	"backend/internal/validate"
 )
 
+// viewDedupWindow は同一訪問者の閲覧を1回として数える期間
+const viewDedupWindow = 30 * time.Minute
+
 // MuseumService はミュージアムのビジネスロジックを含む
 type MuseumService struct {
-	repo repository.MuseumRepository
+	repo           repository.MuseumRepository
+	engagementRepo repository.MuseumEngagementRepository
+	access         *MuseumAccess
+	activity       ActivityRecorder
+	revisions      RevisionRecorder
 }
 
 // NewMuseumService は新しいMuseumServiceを作成する
-func NewMuseumService(repo repository.MuseumRepository) *MuseumService {
-	return &MuseumService{repo: repo}
+func NewMuseumService(repo repository.MuseumRepository, engagementRepo repository.MuseumEngagementRepository, access *MuseumAccess, activity ActivityRecorder, revisions RevisionRecorder) *MuseumService {
+	return &MuseumService{repo: repo, engagementRepo: engagementRepo, access: access, activity: activity, revisions: revisions}
+}
+
+// attachStats はレスポンスにいいね数・閲覧数・いいね済みフラグを付与する
+func (s *MuseumService) attachStats(ctx context.Context, responses []domain.MuseumResponse, callerID int) error {
+	ids := make([]int, len(responses))
+	for i, r := range responses {
+		ids[i] = r.ID
+	}
+
+	stats, err := s.engagementRepo.GetStats(ctx, ids, callerID)
+	if err != nil {
+		return fmt.Errorf("failed to get museum stats: %w", err)
+	}
+
+	for i, r := range responses {
+		responses[i] = r.WithStats(stats[r.ID])
+	}
+	return nil
 }
 
 // GetOtherUsersPublicMuseums は指定ユーザー以外の公開ミュージアムを取得する（ランダム並び替え）
-func (s *MuseumService) GetOtherUsersPublicMuseums(excludeUserID int, limit int) ([]domain.MuseumResponse, error) {
+func (s *MuseumService) GetOtherUsersPublicMuseums(ctx context.Context, excludeUserID int, limit int) ([]domain.MuseumResponse, error) {
 	if excludeUserID <= 0 {
 		return nil, errors.New("invalid user ID")
 	}
@@ -30,7 +60,7 @@ func (s *MuseumService) GetOtherUsersPublicMuseums(excludeUserID int, limit int)
 		limit = 10 // デフォルト値
 	}
 
-	museums, err := s.repo.GetPublicMuseumsExcludingUser(excludeUserID, limit)
+	museums, err := s.repo.GetPublicMuseumsExcludingUser(ctx, excludeUserID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get public museums: %w", err)
 	}
@@ -39,6 +69,9 @@ func (s *MuseumService) GetOtherUsersPublicMuseums(excludeUserID int, limit int)
 	for i, museum := range museums {
 		responses[i] = museum.ToResponse()
 	}
+	if err := s.attachStats(ctx, responses, excludeUserID); err != nil {
+		return nil, err
+	}
 
 	// ランダムに並び替え
 	rand.Seed(time.Now().UnixNano())
@@ -50,50 +83,162 @@ func (s *MuseumService) GetOtherUsersPublicMuseums(excludeUserID int, limit int)
 }
 
 // GetMuseumByID は指定IDのミュージアムを取得する
-func (s *MuseumService) GetMuseumByID(id int) (*domain.MuseumResponse, error) {
+// 閲覧権限のない非公開ミュージアムは存在しないものとして扱う
+// likedByMe はcallerIDのユーザーを基準に判定する（0の場合は常にfalse）
+func (s *MuseumService) GetMuseumByID(ctx context.Context, id int, callerID int) (*domain.MuseumResponse, error) {
+	return s.getMuseum(ctx, id, callerID, false)
+}
+
+// GetSharedMuseum は共有リンク経由でミュージアムを取得する
+// 共有リンクの検証は呼び出し側（ShareService）で済んでいる前提で、公開設定を問わず返す
+func (s *MuseumService) GetSharedMuseum(ctx context.Context, id int, callerID int) (*domain.MuseumResponse, error) {
+	return s.getMuseum(ctx, id, callerID, true)
+}
+
+// getMuseum はミュージアムを取得し、いいね数・閲覧数を付与する
+func (s *MuseumService) getMuseum(ctx context.Context, id int, callerID int, shared bool) (*domain.MuseumResponse, error) {
 	if id <= 0 {
 		return nil, errors.New("invalid museum ID")
 	}
 
-	museum, err := s.repo.FindByID(id)
+	var museum *domain.Museum
+	var err error
+	if shared {
+		museum, err = s.repo.FindByID(ctx, id)
+		if err == nil && museum == nil {
+			err = errors.New("museum not found")
+		}
+	} else {
+		museum, err = s.access.Authorize(ctx, id, callerID, domain.PermissionView)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get museum: %w", err)
+		return nil, err
 	}
-	if museum == nil {
-		return nil, errors.New("museum not found")
+
+	responses := []domain.MuseumResponse{museum.ToResponse()}
+	if err := s.attachStats(ctx, responses, callerID); err != nil {
+		return nil, err
 	}
+	return &responses[0], nil
+}
 
-	response := museum.ToResponse()
-	return &response, nil
+// UpdateTitle はミュージアムのタイトルを更新し、更新後のバージョンを返す。更新できるのは所有者と編集者
+// versionには読み込み時のバージョン（If-Match）を指定し、他で更新されていれば version mismatch を返す（0は確認しない）
+func (s *MuseumService) UpdateTitle(ctx context.Context, id int, userID int, title string, version int) (int, error) {
+	if id <= 0 {
+		return 0, errors.New("invalid museum ID")
+	}
+	if err := validate.Struct(domain.MuseumTitleUpdateRequest{Title: title}); err != nil {
+		return 0, err
+	}
+	if _, err := s.access.Authorize(ctx, id, userID, domain.PermissionEdit); err != nil {
+		return 0, err
+	}
+
+	newVersion, err := s.repo.UpdateTitle(ctx, id, title, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errors.New("museum not found")
+		}
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return 0, errors.New("version mismatch")
+		}
+		return 0, fmt.Errorf("failed to update museum title: %w", err)
+	}
+
+	s.recordRevision(ctx, id, userID, domain.RevisionUpdateTitle)
+
+	return newVersion, nil
 }
 
-// UpdateTitle はミュージアムのタイトルを更新する
-func (s *MuseumService) UpdateTitle(id int, title string) error {
+// UpdateVisibility はミュージアムの公開設定を変更する。変更できるのはowner役割のユーザーのみ
+// 公開に切り替えた場合はフォロワーのフィードに載せる。versionの扱いはUpdateTitleと同じ
+func (s *MuseumService) UpdateVisibility(ctx context.Context, id int, userID int, visibility domain.VisibilityType, version int) (*domain.MuseumResponse, error) {
 	if id <= 0 {
-		return errors.New("invalid museum ID")
+		return nil, errors.New("invalid museum ID")
 	}
-	if title == "" {
-		return errors.New("title cannot be empty")
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+	if err := validate.Struct(domain.MuseumVisibilityUpdateRequest{Visibility: visibility}); err != nil {
+		return nil, err
 	}
 
-	err := s.repo.UpdateTitle(id, title)
+	museum, err := s.access.Authorize(ctx, id, userID, domain.PermissionManage)
 	if err != nil {
+		return nil, err
+	}
+
+	if version > 0 && museum.Version != version {
+		return nil, errors.New("version mismatch")
+	}
+
+	if museum.Visibility != visibility {
+		if _, err := s.repo.UpdateVisibility(ctx, id, visibility, version); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New("museum not found")
+			}
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return nil, errors.New("version mismatch")
+			}
+			return nil, fmt.Errorf("failed to update museum visibility: %w", err)
+		}
+		s.recordRevision(ctx, id, userID, domain.RevisionUpdateVisibility)
+		if visibility == domain.VisibilityPublic {
+			s.recordActivity(ctx, userID, domain.ActivityMuseumPublished, id)
+		}
+	}
+
+	return s.GetMuseumByID(ctx, id, userID)
+}
+
+// DeleteMuseum はミュージアムをゴミ箱に移す。削除できるのはowner役割のユーザーのみ
+// 保持期間内であればTrashServiceで元に戻せる
+func (s *MuseumService) DeleteMuseum(ctx context.Context, id int, userID int) error {
+	if userID <= 0 {
+		return errors.New("invalid user ID")
+	}
+	if _, err := s.access.Authorize(ctx, id, userID, domain.PermissionManage); err != nil {
+		return err
+	}
+
+	if err := s.repo.SoftDelete(ctx, id); err != nil {
 		if err == sql.ErrNoRows {
 			return errors.New("museum not found")
 		}
-		return fmt.Errorf("failed to update museum title: %w", err)
+		return fmt.Errorf("failed to delete museum: %w", err)
 	}
-
 	return nil
 }
 
+// recordActivity はフィード用のアクティビティを記録する
+func (s *MuseumService) recordActivity(ctx context.Context, actorID int, activityType domain.ActivityType, museumID int) {
+	if s.activity == nil {
+		return
+	}
+	s.activity.Record(ctx, domain.Activity{
+		ActorID:  actorID,
+		Type:     activityType,
+		MuseumID: museumID,
+	})
+}
+
+// recordRevision は変更後のミュージアムをリビジョンとして記録する
+func (s *MuseumService) recordRevision(ctx context.Context, museumID int, authorID int, action domain.RevisionAction) {
+	if s.revisions == nil {
+		return
+	}
+	s.revisions.Record(ctx, museumID, authorID, action)
+}
+
 // Create は新しいミュージアムを作成する
-func (s *MuseumService) Create(req domain.MuseumCreateRequest) (*domain.MuseumResponse, error) {
-	if req.UserID <= 0 {
-		return nil, errors.New("invalid user ID")
+// リクエストはvalidateタグのルールで検証し、失敗したフィールドをまとめてvalidate.Errorsで返す
+func (s *MuseumService) Create(ctx context.Context, req domain.MuseumCreateRequest) (*domain.MuseumResponse, error) {
+	if err := validate.Struct(req); err != nil {
+		return nil, err
 	}
-	if req.Name == "" {
-		return nil, errors.New("museum name cannot be empty")
+	if req.Visibility == "" {
+		req.Visibility = domain.VisibilityPrivate
 	}
 
 	museum := domain.Museum{
@@ -104,11 +249,164 @@ func (s *MuseumService) Create(req domain.MuseumCreateRequest) (*domain.MuseumRe
 		ImageURL:    req.ImageURL,
 	}
 
-	createdMuseum, err := s.repo.Insert(museum)
+	createdMuseum, err := s.repo.Insert(ctx, museum)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create museum: %w", err)
 	}
 
+	metrics.MuseumsCreated.WithLabelValues("create").Inc()
+	s.recordActivity(ctx, createdMuseum.UserID, domain.ActivityMuseumCreated, createdMuseum.ID)
+	s.recordRevision(ctx, createdMuseum.ID, createdMuseum.UserID, domain.RevisionCreate)
+
 	response := createdMuseum.ToResponse()
 	return &response, nil
-}
\ No newline at end of file
+}
+
+// ForkMuseum はミュージアムを複製し、呼び出しユーザーが所有する非公開ミュージアムを作成する
+// 複製できるのは公開ミュージアムと、自分が作成・参加しているミュージアム
+func (s *MuseumService) ForkMuseum(ctx context.Context, id int, userID int) (*domain.MuseumResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	source, err := s.access.Authorize(ctx, id, userID, domain.PermissionView)
+	if err != nil {
+		return nil, err
+	}
+	if !source.IsPublic() {
+		role, err := s.access.Role(ctx, source, userID)
+		if err != nil {
+			return nil, err
+		}
+		if role == "" {
+			return nil, errors.New("permission denied")
+		}
+	}
+
+	forked, err := s.repo.Fork(ctx, id, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fork museum: %w", err)
+	}
+	if forked == nil {
+		return nil, errors.New("museum not found")
+	}
+
+	metrics.MuseumsCreated.WithLabelValues("fork").Inc()
+	s.recordActivity(ctx, userID, domain.ActivityMuseumCreated, forked.ID)
+	s.recordRevision(ctx, forked.ID, userID, domain.RevisionFork)
+
+	response := forked.ToResponse()
+	return &response, nil
+}
+
+// SearchMuseums は名前・説明文でミュージアムを検索する
+// 公開ミュージアムに加え、callerIDのユーザーが作成・参加しているミュージアム（非公開含む）も対象になる
+func (s *MuseumService) SearchMuseums(ctx context.Context, query string, callerID int, limit, offset int) (*domain.MuseumSearchResponse, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is required")
+	}
+	if utf8.RuneCountInString(query) > 100 {
+		return nil, errors.New("search query is too long (max 100)")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20 // デフォルト値
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	museums, total, err := s.repo.Search(ctx, query, callerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search museums: %w", err)
+	}
+
+	responses := make([]domain.MuseumResponse, len(museums))
+	for i, museum := range museums {
+		responses[i] = museum.ToResponse()
+	}
+	if err := s.attachStats(ctx, responses, callerID); err != nil {
+		return nil, err
+	}
+
+	return &domain.MuseumSearchResponse{
+		Query:   query,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		Museums: responses,
+	}, nil
+}
+
+// LikeMuseum はミュージアムにいいねを付ける。既にいいね済みでもエラーにはならない
+func (s *MuseumService) LikeMuseum(ctx context.Context, id int, userID int) (*domain.MuseumLikeResponse, error) {
+	if err := s.ensureLikable(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.engagementRepo.Like(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("failed to like museum: %w", err)
+	}
+
+	return s.likeResponse(ctx, id, userID)
+}
+
+// UnlikeMuseum はミュージアムのいいねを取り消す。いいねしていなくてもエラーにはならない
+func (s *MuseumService) UnlikeMuseum(ctx context.Context, id int, userID int) (*domain.MuseumLikeResponse, error) {
+	if err := s.ensureLikable(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.engagementRepo.Unlike(ctx, id, userID); err != nil {
+		return nil, fmt.Errorf("failed to unlike museum: %w", err)
+	}
+
+	return s.likeResponse(ctx, id, userID)
+}
+
+// RecordView はミュージアムの閲覧を記録する
+// visitorKeyが同じ訪問者の再閲覧は一定期間（viewDedupWindow）内は数えない
+func (s *MuseumService) RecordView(ctx context.Context, id int, visitorKey string) error {
+	if id <= 0 {
+		return errors.New("invalid museum ID")
+	}
+	if visitorKey == "" {
+		return nil
+	}
+
+	if _, err := s.engagementRepo.RecordView(ctx, id, visitorKey, viewDedupWindow); err != nil {
+		return fmt.Errorf("failed to record museum view: %w", err)
+	}
+	return nil
+}
+
+// ensureLikable はユーザーがミュージアムにいいねできるか（閲覧できるか）確認する
+func (s *MuseumService) ensureLikable(ctx context.Context, id int, userID int) error {
+	if id <= 0 {
+		return errors.New("invalid museum ID")
+	}
+	if userID <= 0 {
+		return errors.New("invalid user ID")
+	}
+
+	_, err := s.access.Authorize(ctx, id, userID, domain.PermissionView)
+	return err
+}
+
+// likeResponse はいいね操作後の集計を返す
+func (s *MuseumService) likeResponse(ctx context.Context, id int, userID int) (*domain.MuseumLikeResponse, error) {
+	stats, err := s.engagementRepo.GetStats(ctx, []int{id}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get museum stats: %w", err)
+	}
+
+	st := stats[id]
+	return &domain.MuseumLikeResponse{
+		MuseumID:  id,
+		LikeCount: st.LikeCount,
+		LikedByMe: st.LikedByMe,
+	}, nil
+}
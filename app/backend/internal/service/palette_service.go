@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+const (
+	paletteColors       = 5                // 代表色の数（k-meansのk）
+	paletteImageSize    = 200              // 代表色の計算に使う縮小画像の大きさ
+	paletteMaxIter      = 20               // k-meansの反復回数の上限
+	paletteQueueSize    = 256              // 一覧の表示で見つかった未計算の作品を待たせておく数
+	paletteBackfillSize = 20               // バックフィル1回で計算する作品数
+	paletteRetryAfter   = time.Hour        // 計算に失敗した作品をバックフィルで再び試すまでの時間
+	paletteTimeout      = 30 * time.Second // 1作品の計算のタイムアウト
+)
+
+// PaletteLookup は作品の代表色を取得する
+// 一覧に付け加えるだけなので、取得の失敗で一覧自体を失敗させないようエラーは返さない
+// 未計算の作品は結果に含めず、バックグラウンドでの計算を予約する
+type PaletteLookup interface {
+	Lookup(ctx context.Context, objectIDs []int) map[int]domain.ArtworkPalette
+}
+
+// PaletteService は作品画像の代表色（k-means）と平均の明るさを計算して保存する
+// 計算は初めて必要になったときに行い、展示中の未計算の作品はバックグラウンドで埋める
+type PaletteService struct {
+	repo     repository.ArtworkPaletteRepository
+	images   *ImageService
+	log      *slog.Logger
+	inflight singleflight.Group
+	queue    chan int
+}
+
+// NewPaletteService は新しいPaletteServiceを作成する
+func NewPaletteService(repo repository.ArtworkPaletteRepository, images *ImageService, log *slog.Logger) *PaletteService {
+	return &PaletteService{repo: repo, images: images, log: log, queue: make(chan int, paletteQueueSize)}
+}
+
+// Get は作品の代表色を返す。未計算なら計算して保存する
+func (s *PaletteService) Get(ctx context.Context, objectID int) (*domain.ArtworkPalette, error) {
+	if objectID <= 0 {
+		return nil, errors.New("invalid object ID")
+	}
+	found, err := s.repo.FindByObjectIDs(ctx, []int{objectID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get palette: %w", err)
+	}
+	if p, ok := found[objectID]; ok {
+		return &p, nil
+	}
+	return s.compute(ctx, objectID)
+}
+
+// Lookup implements PaletteLookup.
+func (s *PaletteService) Lookup(ctx context.Context, objectIDs []int) map[int]domain.ArtworkPalette {
+	found, err := s.repo.FindByObjectIDs(ctx, objectIDs)
+	if err != nil {
+		s.log.Error("failed to look up palettes", slog.String("error", err.Error()), slog.Int("count", len(objectIDs)))
+		return nil
+	}
+	for _, id := range objectIDs {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		// 待ちがいっぱいなら諦める（バックフィルか次の表示で計算される）
+		select {
+		case s.queue <- id:
+		default:
+		}
+	}
+	return found
+}
+
+// RunBackfill はctxがキャンセルされるまで、Lookupで予約された作品と
+// intervalごとに探した展示中の未計算の作品の代表色を計算する
+func (s *PaletteService) RunBackfill(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// 失敗した作品はしばらく試さない（このゴルーチンだけが触る）
+	failedUntil := map[int]time.Time{}
+	run := func(objectID int) {
+		if until, ok := failedUntil[objectID]; ok && time.Now().Before(until) {
+			return
+		}
+		if _, err := s.Get(ctx, objectID); err != nil {
+			if ctx.Err() == nil {
+				s.log.Warn("palette backfill failed", slog.String("error", err.Error()), slog.Int("objectId", objectID))
+			}
+			failedUntil[objectID] = time.Now().Add(paletteRetryAfter)
+			return
+		}
+		delete(failedUntil, objectID)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			run(id)
+		case <-ticker.C:
+			exclude := make([]int, 0, len(failedUntil))
+			for id, until := range failedUntil {
+				if time.Now().Before(until) {
+					exclude = append(exclude, id)
+				} else {
+					delete(failedUntil, id)
+				}
+			}
+			ids, err := s.repo.ListMissing(ctx, exclude, paletteBackfillSize)
+			if err != nil {
+				s.log.Error("failed to list artworks without palette", slog.String("error", err.Error()))
+				continue
+			}
+			for _, id := range ids {
+				run(id)
+			}
+			if len(ids) > 0 {
+				s.log.Info("palette backfill", slog.Int("count", len(ids)))
+			}
+		}
+	}
+}
+
+// compute はキャッシュ済みの縮小画像から代表色を計算して保存する
+// 同じ作品の計算が同時に要求された場合は1回だけ行う
+func (s *PaletteService) compute(ctx context.Context, objectID int) (*domain.ArtworkPalette, error) {
+	v, err, _ := s.inflight.Do(strconv.Itoa(objectID), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), paletteTimeout)
+		defer cancel()
+
+		p := domain.ArtworkPalette{ObjectID: objectID, Colors: []domain.PaletteColor{}, ComputedAt: time.Now()}
+		img, err := s.images.GetImage(ctx, objectID, ImageOptions{Width: paletteImageSize, Height: paletteImageSize})
+		switch {
+		case errors.Is(err, ErrArtworkHasNoImage):
+			// 画像のない作品は空の代表色を保存し、何度も取得しにいかないようにする
+		case err != nil:
+			return nil, err
+		default:
+			decoded, err := jpeg.Decode(bytes.NewReader(img.Data))
+			if err != nil {
+				return nil, fmt.Errorf("decode image: %w", err)
+			}
+			p.Colors, p.Brightness = extractPalette(decoded, paletteColors)
+		}
+
+		if err := s.repo.Upsert(ctx, p); err != nil {
+			return nil, fmt.Errorf("failed to save palette: %w", err)
+		}
+		return &p, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*domain.ArtworkPalette), nil
+}
+
+// extractPalette は画像の画素をk-meansでk色以下にまとめ、割合の大きい順の代表色と平均の輝度を返す
+// 同じ画像から同じ結果になるよう、初期値（k-means++）の乱数は固定のシードを使う
+func extractPalette(img image.Image, k int) ([]domain.PaletteColor, float64) {
+	b := img.Bounds()
+	pixels := make([][3]float64, 0, b.Dx()*b.Dy())
+	var luminance float64
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			px := [3]float64{float64(r >> 8), float64(g >> 8), float64(bl >> 8)}
+			pixels = append(pixels, px)
+			luminance += (0.2126*px[0] + 0.7152*px[1] + 0.0722*px[2]) / 255
+		}
+	}
+	if len(pixels) == 0 {
+		return []domain.PaletteColor{}, 0
+	}
+
+	centers := initCenters(pixels, k, rand.New(rand.NewPCG(1, 2)))
+	assign := make([]int, len(pixels))
+	counts := make([]int, len(centers))
+	for iter := 0; iter < paletteMaxIter; iter++ {
+		changed := iter == 0
+		for i, px := range pixels {
+			if c := nearest(centers, px); c != assign[i] {
+				assign[i], changed = c, true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][3]float64, len(centers))
+		clear(counts)
+		for i, px := range pixels {
+			c := assign[i]
+			counts[c]++
+			for j := range px {
+				sums[c][j] += px[j]
+			}
+		}
+		for c := range centers {
+			if counts[c] == 0 {
+				continue // 空のクラスタは中心を動かさない
+			}
+			for j := range sums[c] {
+				centers[c][j] = sums[c][j] / float64(counts[c])
+			}
+		}
+	}
+
+	colors := make([]domain.PaletteColor, 0, len(centers))
+	for c, center := range centers {
+		if counts[c] == 0 {
+			continue
+		}
+		colors = append(colors, domain.PaletteColor{
+			Hex:   fmt.Sprintf("#%02x%02x%02x", uint8(center[0]+0.5), uint8(center[1]+0.5), uint8(center[2]+0.5)),
+			Ratio: float64(counts[c]) / float64(len(pixels)),
+		})
+	}
+	slices.SortStableFunc(colors, func(a, b domain.PaletteColor) int {
+		switch {
+		case a.Ratio > b.Ratio:
+			return -1
+		case a.Ratio < b.Ratio:
+			return 1
+		}
+		return 0
+	})
+	return colors, luminance / float64(len(pixels))
+}
+
+// initCenters はk-means++で初期の中心を選ぶ（色の種類がkより少なければその数だけ返す）
+func initCenters(pixels [][3]float64, k int, rng *rand.Rand) [][3]float64 {
+	centers := [][3]float64{pixels[rng.IntN(len(pixels))]}
+	dist := make([]float64, len(pixels))
+	for len(centers) < k {
+		var total float64
+		for i, px := range pixels {
+			dist[i] = sqDist(px, centers[nearest(centers, px)])
+			total += dist[i]
+		}
+		if total == 0 {
+			break
+		}
+		target := rng.Float64() * total
+		next := len(pixels) - 1
+		for i, d := range dist {
+			if target -= d; target < 0 {
+				next = i
+				break
+			}
+		}
+		centers = append(centers, pixels[next])
+	}
+	return centers
+}
+
+// nearest はpxに最も近い中心の添字を返す
+func nearest(centers [][3]float64, px [3]float64) int {
+	best, bestDist := 0, sqDist(centers[0], px)
+	for c := 1; c < len(centers); c++ {
+		if d := sqDist(centers[c], px); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+func sqDist(a, b [3]float64) float64 {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+func TestExtractPalette(t *testing.T) {
+	// 左3/4が赤、右1/4が白の画像
+	img := image.NewRGBA(image.Rect(0, 0, 40, 10))
+	draw.Draw(img, image.Rect(0, 0, 30, 10), image.NewUniform(color.RGBA{R: 255, A: 255}), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(30, 0, 40, 10), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	colors, brightness := extractPalette(img, 5)
+	if len(colors) != 2 {
+		t.Fatalf("expected 2 colors for a two-colored image, got %+v", colors)
+	}
+	if colors[0].Hex != "#ff0000" || colors[0].Ratio != 0.75 {
+		t.Fatalf("expected red to dominate, got %+v", colors[0])
+	}
+	if colors[1].Hex != "#ffffff" || colors[1].Ratio != 0.25 {
+		t.Fatalf("expected white as the second color, got %+v", colors[1])
+	}
+	if want := 0.75*0.2126 + 0.25; math.Abs(brightness-want) > 1e-9 {
+		t.Fatalf("expected brightness %v, got %v", want, brightness)
+	}
+
+	// 同じ画像からは同じ結果になる
+	again, _ := extractPalette(img, 5)
+	if again[0] != colors[0] || again[1] != colors[1] {
+		t.Fatalf("expected deterministic palette, got %+v and %+v", colors, again)
+	}
+}
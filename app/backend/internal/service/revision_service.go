@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// RevisionRecorder はミュージアムの変更後の状態をリビジョンとして記録する
+// 記録の失敗で元の操作（タイトル変更など）を失敗させないよう、エラーは返さない
+type RevisionRecorder interface {
+	Record(ctx context.Context, museumID int, authorID int, action domain.RevisionAction)
+}
+
+// RevisionService はミュージアムの変更履歴のビジネスロジックを含む
+type RevisionService struct {
+	repo   repository.MuseumRevisionRepository
+	access *MuseumAccess
+	log    *slog.Logger
+}
+
+// NewRevisionService は新しいRevisionServiceを作成する
+func NewRevisionService(repo repository.MuseumRevisionRepository, access *MuseumAccess, log *slog.Logger) *RevisionService {
+	return &RevisionService{repo: repo, access: access, log: log}
+}
+
+// Record はミュージアムの現在の状態をリビジョンとして記録する。失敗した場合はログに残すのみ
+func (s *RevisionService) Record(ctx context.Context, museumID int, authorID int, action domain.RevisionAction) {
+	if _, err := s.repo.Snapshot(ctx, museumID, authorID, action); err != nil {
+		s.log.Error("failed to record museum revision",
+			slog.String("error", err.Error()),
+			slog.String("action", string(action)),
+			slog.Int("museumId", museumID),
+			slog.Int("authorId", authorID),
+		)
+	}
+}
+
+// ListRevisions はミュージアムのリビジョンを新しい順に取得する。閲覧できるのは所有者と編集者
+func (s *RevisionService) ListRevisions(ctx context.Context, museumID int, userID int, limit, offset int) (*domain.MuseumRevisionPageResponse, error) {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20 // デフォルト値
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	revisions, total, err := s.repo.List(ctx, museumID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+
+	summaries := make([]domain.MuseumRevisionSummary, len(revisions))
+	for i, rev := range revisions {
+		summaries[i] = rev.Summary()
+	}
+
+	return &domain.MuseumRevisionPageResponse{
+		Total:     total,
+		Limit:     limit,
+		Offset:    offset,
+		Revisions: summaries,
+	}, nil
+}
+
+// GetRevision はスナップショットを含むリビジョンを取得する。閲覧できるのは所有者と編集者
+func (s *RevisionService) GetRevision(ctx context.Context, museumID int, revisionID int, userID int) (*domain.MuseumRevision, error) {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	return s.findRevision(ctx, museumID, revisionID)
+}
+
+// Diff はfromからtoへの変更内容を返す。閲覧できるのは所有者と編集者
+func (s *RevisionService) Diff(ctx context.Context, museumID int, fromID, toID int, userID int) (*domain.MuseumRevisionDiff, error) {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+
+	from, err := s.findRevision(ctx, museumID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(ctx, museumID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := domain.DiffSnapshots(from.Snapshot, to.Snapshot)
+	diff.From, diff.To = from.ID, to.ID
+	return &diff, nil
+}
+
+// Restore は過去のリビジョンの内容（部屋の配置を含む）にミュージアムを戻す。復元できるのは所有者と編集者
+// 復元自体も新しいリビジョンとして記録される。公開設定は復元しない
+func (s *RevisionService) Restore(ctx context.Context, museumID int, revisionID int, userID int) (*domain.MuseumRevision, error) {
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return nil, err
+	}
+	if revisionID <= 0 {
+		return nil, errors.New("invalid revision ID")
+	}
+
+	rev, err := s.repo.Restore(ctx, museumID, revisionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore revision: %w", err)
+	}
+	if rev == nil {
+		return nil, errors.New("revision not found")
+	}
+	return rev, nil
+}
+
+// findRevision はミュージアムに属するリビジョンを取得する
+func (s *RevisionService) findRevision(ctx context.Context, museumID int, revisionID int) (*domain.MuseumRevision, error) {
+	if revisionID <= 0 {
+		return nil, errors.New("invalid revision ID")
+	}
+
+	rev, err := s.repo.FindByID(ctx, revisionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision: %w", err)
+	}
+	if rev == nil || rev.MuseumID != museumID {
+		return nil, errors.New("revision not found")
+	}
+	return rev, nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// fakeRevisionRepo はリビジョンをメモリに持つMuseumRevisionRepository
+type fakeRevisionRepo struct {
+	repository.MuseumRevisionRepository
+	revisions map[int]*domain.MuseumRevision
+	restored  []int // Restoreに渡されたリビジョンID
+}
+
+func (r *fakeRevisionRepo) FindByID(_ context.Context, id int) (*domain.MuseumRevision, error) {
+	return r.revisions[id], nil
+}
+
+func (r *fakeRevisionRepo) Restore(_ context.Context, museumID, revisionID, authorID int) (*domain.MuseumRevision, error) {
+	src := r.revisions[revisionID]
+	if src == nil || src.MuseumID != museumID {
+		return nil, nil
+	}
+	r.restored = append(r.restored, revisionID)
+	from := revisionID
+	return &domain.MuseumRevision{ID: 100, MuseumID: museumID, Action: domain.RevisionRestore, RestoredFrom: &from, Snapshot: src.Snapshot}, nil
+}
+
+func newTestRevisionService() (*RevisionService, *fakeRevisionRepo) {
+	repo := &fakeRevisionRepo{revisions: map[int]*domain.MuseumRevision{
+		1: {ID: 1, MuseumID: 10, Snapshot: domain.MuseumSnapshot{Name: "before"}},
+		2: {ID: 2, MuseumID: 10, Snapshot: domain.MuseumSnapshot{Name: "after"}},
+		3: {ID: 3, MuseumID: 11, Snapshot: domain.MuseumSnapshot{Name: "other museum"}},
+	}}
+	return NewRevisionService(repo, newTestMuseumAccess(domain.VisibilityPublic), nil), repo
+}
+
+func TestRevisionRestore(t *testing.T) {
+	s, repo := newTestRevisionService()
+
+	rev, err := s.Restore(context.Background(), 10, 1, accessEditor)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rev.Action != domain.RevisionRestore || rev.RestoredFrom == nil || *rev.RestoredFrom != 1 || rev.Snapshot.Name != "before" {
+		t.Errorf("unexpected restored revision %+v", rev)
+	}
+
+	tests := []struct {
+		name       string
+		revisionID int
+		userID     int
+		want       string
+	}{
+		{"viewer cannot restore", 1, accessViewer, "permission denied"},
+		{"revision of another museum", 3, accessOwner, "revision not found"},
+		{"missing revision", 99, accessOwner, "revision not found"},
+		{"invalid revision ID", 0, accessOwner, "invalid revision ID"},
+	}
+	for _, tt := range tests {
+		if _, err := s.Restore(context.Background(), 10, tt.revisionID, tt.userID); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: got %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if len(repo.restored) != 1 {
+		t.Errorf("expected only the permitted restore to reach the repository, got %v", repo.restored)
+	}
+}
+
+func TestRevisionDiff(t *testing.T) {
+	s, _ := newTestRevisionService()
+
+	diff, err := s.Diff(context.Background(), 10, 1, 2, accessOwner)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diff.From != 1 || diff.To != 2 || len(diff.Fields) != 1 || diff.Fields[0].To != "after" {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+
+	// 他のミュージアムのリビジョンとは比較できない
+	if _, err := s.Diff(context.Background(), 10, 1, 3, accessOwner); err == nil || err.Error() != "revision not found" {
+		t.Errorf("expected revision not found, got %v", err)
+	}
+}
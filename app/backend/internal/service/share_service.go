@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// maxShareTokenHours は共有リンクの有効期限の上限（1年）
+const maxShareTokenHours = 24 * 365
+
+// ShareService はミュージアム共有リンクのビジネスロジックを含む
+type ShareService struct {
+	repo   repository.ShareTokenRepository
+	access *MuseumAccess
+}
+
+// NewShareService は新しいShareServiceを作成する
+func NewShareService(repo repository.ShareTokenRepository, access *MuseumAccess) *ShareService {
+	return &ShareService{repo: repo, access: access}
+}
+
+// CreateToken は共有リンクを発行する。発行できるのはowner役割のユーザーのみ
+// 生のトークンはこのレスポンスでしか返さない
+func (s *ShareService) CreateToken(ctx context.Context, museumID int, userID int, req domain.ShareTokenCreateRequest) (*domain.ShareTokenResponse, error) {
+	if _, err := s.ownedMuseum(ctx, museumID, userID); err != nil {
+		return nil, err
+	}
+
+	scope := req.Scope
+	if scope == "" {
+		scope = domain.ShareScopeView
+	}
+	if scope != domain.ShareScopeView {
+		return nil, errors.New("invalid share scope")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInHours != nil {
+		hours := *req.ExpiresInHours
+		if hours <= 0 || hours > maxShareTokenHours {
+			return nil, errors.New("invalid expiresInHours")
+		}
+		t := time.Now().UTC().Add(time.Duration(hours) * time.Hour)
+		expiresAt = &t
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share token: %w", err)
+	}
+
+	created, err := s.repo.Insert(ctx, domain.ShareToken{
+		MuseumID:  museumID,
+		TokenHash: hashShareToken(token),
+		Scope:     scope,
+		CreatedBy: userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share token: %w", err)
+	}
+
+	response := created.ToResponse(time.Now())
+	response.Token = token
+	return &response, nil
+}
+
+// ListTokens はミュージアムの共有リンク一覧を取得する（owner役割のみ）
+func (s *ShareService) ListTokens(ctx context.Context, museumID int, userID int) ([]domain.ShareTokenResponse, error) {
+	if _, err := s.ownedMuseum(ctx, museumID, userID); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.repo.ListByMuseum(ctx, museumID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share tokens: %w", err)
+	}
+
+	now := time.Now()
+	responses := make([]domain.ShareTokenResponse, len(tokens))
+	for i, t := range tokens {
+		responses[i] = t.ToResponse(now)
+	}
+	return responses, nil
+}
+
+// RevokeToken は共有リンクを無効化する（owner役割のみ）
+func (s *ShareService) RevokeToken(ctx context.Context, museumID int, tokenID int, userID int) error {
+	if _, err := s.ownedMuseum(ctx, museumID, userID); err != nil {
+		return err
+	}
+	if tokenID <= 0 {
+		return errors.New("invalid share token ID")
+	}
+
+	if err := s.repo.Revoke(ctx, tokenID, museumID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("share link not found")
+		}
+		return fmt.Errorf("failed to revoke share token: %w", err)
+	}
+	return nil
+}
+
+// Resolve はトークンを検証し、有効な共有リンクを返す
+// 存在しない・期限切れ・無効化済みはいずれも同じエラーにして区別できないようにする
+func (s *ShareService) Resolve(ctx context.Context, token string) (*domain.ShareToken, error) {
+	if token == "" {
+		return nil, errors.New("share link not found")
+	}
+
+	t, err := s.repo.FindByHash(ctx, hashShareToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share token: %w", err)
+	}
+	if t == nil || !t.IsActive(time.Now()) {
+		return nil, errors.New("share link not found")
+	}
+	return t, nil
+}
+
+// ownedMuseum は呼び出しユーザーがowner役割を持つミュージアムを取得する
+func (s *ShareService) ownedMuseum(ctx context.Context, museumID int, userID int) (*domain.Museum, error) {
+	return s.access.Authorize(ctx, museumID, userID, domain.PermissionManage)
+}
+
+// generateShareToken はURLに埋め込める推測困難なトークンを生成する
+func generateShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashShareToken は保存・照合用にトークンをハッシュ化する
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// TrashService はゴミ箱（論理削除したミュージアム・展示作品）のビジネスロジックを含む
+type TrashService struct {
+	museumRepo  repository.MuseumRepository
+	artworkRepo repository.MuseumArtworkRepository
+	access      *MuseumAccess
+	revisions   RevisionRecorder
+	retention   time.Duration
+	log         *slog.Logger
+}
+
+// NewTrashService は新しいTrashServiceを作成する
+// retentionを過ぎたゴミ箱の項目はPurgeで物理削除される
+func NewTrashService(museumRepo repository.MuseumRepository, artworkRepo repository.MuseumArtworkRepository, access *MuseumAccess, revisions RevisionRecorder, retention time.Duration, log *slog.Logger) *TrashService {
+	return &TrashService{
+		museumRepo:  museumRepo,
+		artworkRepo: artworkRepo,
+		access:      access,
+		revisions:   revisions,
+		retention:   retention,
+		log:         log,
+	}
+}
+
+// ListTrash はユーザーのゴミ箱を取得する
+// ミュージアムは作成者とowner役割のユーザー、展示作品は所有者と編集者のゴミ箱に表示される
+func (s *TrashService) ListTrash(ctx context.Context, userID int) (*domain.TrashResponse, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	museums, err := s.museumRepo.ListDeleted(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted museums: %w", err)
+	}
+	artworks, err := s.artworkRepo.ListDeleted(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted artworks: %w", err)
+	}
+
+	responses := make([]domain.MuseumResponse, len(museums))
+	for i, museum := range museums {
+		responses[i] = museum.ToResponse()
+	}
+
+	return &domain.TrashResponse{
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+		Museums:       responses,
+		Artworks:      artworks,
+	}, nil
+}
+
+// RestoreMuseum はゴミ箱のミュージアムを元に戻す。戻せるのはowner役割のユーザーのみ
+func (s *TrashService) RestoreMuseum(ctx context.Context, id int, userID int) (*domain.MuseumResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid museum ID")
+	}
+	if userID <= 0 {
+		return nil, errors.New("invalid user ID")
+	}
+
+	museum, err := s.museumRepo.FindDeleted(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get museum: %w", err)
+	}
+	if museum == nil {
+		return nil, errors.New("museum not found")
+	}
+
+	role, err := s.access.Role(ctx, museum, userID)
+	if err != nil {
+		return nil, err
+	}
+	if role == "" {
+		return nil, errors.New("museum not found")
+	}
+	if !role.Can(domain.PermissionManage) {
+		return nil, errors.New("permission denied")
+	}
+
+	if err := s.museumRepo.Restore(ctx, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("museum not found")
+		}
+		return nil, fmt.Errorf("failed to restore museum: %w", err)
+	}
+
+	museum.DeletedAt = nil
+	response := museum.ToResponse()
+	return &response, nil
+}
+
+// RestoreArtwork はゴミ箱の展示作品を元に戻す。戻せるのは所有者と編集者
+func (s *TrashService) RestoreArtwork(ctx context.Context, museumID int, objectID int, userID int) error {
+	if objectID <= 0 {
+		return errors.New("invalid object ID")
+	}
+	if userID <= 0 {
+		return errors.New("invalid user ID")
+	}
+	if _, err := s.access.Authorize(ctx, museumID, userID, domain.PermissionEdit); err != nil {
+		return err
+	}
+
+	if err := s.artworkRepo.Restore(ctx, museumID, objectID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("artwork not found")
+		}
+		return fmt.Errorf("failed to restore artwork: %w", err)
+	}
+
+	if s.revisions != nil {
+		s.revisions.Record(ctx, museumID, userID, domain.RevisionRestoreArtwork)
+	}
+	return nil
+}
+
+// Purge は保持期間を過ぎたゴミ箱のミュージアム・展示作品を物理削除する
+func (s *TrashService) Purge(ctx context.Context) (museums int64, artworks int64, err error) {
+	before := time.Now().Add(-s.retention)
+
+	museums, err = s.museumRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge museums: %w", err)
+	}
+	artworks, err = s.artworkRepo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return museums, 0, fmt.Errorf("failed to purge artworks: %w", err)
+	}
+	return museums, artworks, nil
+}
+
+// RunPurge はctxがキャンセルされるまでintervalごとにPurgeを実行する
+func (s *TrashService) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		museums, artworks, err := s.Purge(ctx)
+		if err != nil {
+			s.log.Error("trash purge failed", slog.String("error", err.Error()))
+		} else if museums > 0 || artworks > 0 {
+			s.log.Info("trash purged", slog.Int64("museums", museums), slog.Int64("artworks", artworks))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
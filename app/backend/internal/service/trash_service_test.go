@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"backend/internal/domain"
+	"backend/internal/repository"
+)
+
+// trashMuseumRepo はゴミ箱のミュージアムを保持するMuseumRepository
+type trashMuseumRepo struct {
+	repository.MuseumRepository
+	deleted      map[int]*domain.Museum
+	restored     []int
+	purgedBefore time.Time
+}
+
+func (r *trashMuseumRepo) FindDeleted(_ context.Context, id int) (*domain.Museum, error) {
+	return r.deleted[id], nil
+}
+
+func (r *trashMuseumRepo) Restore(_ context.Context, id int) error {
+	if r.deleted[id] == nil {
+		return sql.ErrNoRows
+	}
+	r.restored = append(r.restored, id)
+	return nil
+}
+
+func (r *trashMuseumRepo) PurgeDeleted(_ context.Context, before time.Time) (int64, error) {
+	r.purgedBefore = before
+	return 1, nil
+}
+
+// trashArtworkRepo はゴミ箱の展示作品を保持するMuseumArtworkRepository
+type trashArtworkRepo struct {
+	repository.MuseumArtworkRepository
+	deleted      map[int]bool // オブジェクトID
+	purgedBefore time.Time
+}
+
+func (r *trashArtworkRepo) Restore(_ context.Context, _, objectID int) error {
+	if !r.deleted[objectID] {
+		return sql.ErrNoRows
+	}
+	delete(r.deleted, objectID)
+	return nil
+}
+
+func (r *trashArtworkRepo) PurgeDeleted(_ context.Context, before time.Time) (int64, error) {
+	r.purgedBefore = before
+	return 2, nil
+}
+
+const trashRetention = 30 * 24 * time.Hour
+
+func newTestTrashService(revisions RevisionRecorder) (*TrashService, *trashMuseumRepo, *trashArtworkRepo) {
+	museums := &trashMuseumRepo{deleted: map[int]*domain.Museum{
+		20: {ID: 20, UserID: accessOwner, Visibility: domain.VisibilityPrivate},
+	}}
+	artworks := &trashArtworkRepo{deleted: map[int]bool{100: true}}
+	s := NewTrashService(museums, artworks, newTestMuseumAccess(domain.VisibilityPublic), revisions, trashRetention, nil)
+	return s, museums, artworks
+}
+
+func TestRestoreMuseum(t *testing.T) {
+	const (
+		ok       = ""
+		notFound = "museum not found"
+		denied   = "permission denied"
+		noUser   = "invalid user ID"
+	)
+	// 役割のないユーザーにはゴミ箱のミュージアムの存在を明かさない
+	want := map[int]string{
+		accessOwner:     ok,
+		accessEditor:    denied,
+		accessViewer:    denied,
+		accessStranger:  notFound,
+		accessAnonymous: noUser,
+	}
+	for user, wantErr := range want {
+		s, museums, _ := newTestTrashService(nil)
+		res, err := s.RestoreMuseum(context.Background(), 20, user)
+		got := ok
+		if err != nil {
+			got = err.Error()
+		}
+		if got != wantErr {
+			t.Errorf("user %d: got %q, want %q", user, got, wantErr)
+		}
+		if err == nil && (res.ID != 20 || len(museums.restored) != 1) {
+			t.Errorf("user %d: museum not restored (response %+v, restored %v)", user, res, museums.restored)
+		}
+		if err != nil && len(museums.restored) != 0 {
+			t.Errorf("user %d: museum restored despite %v", user, err)
+		}
+	}
+
+	s, _, _ := newTestTrashService(nil)
+	if _, err := s.RestoreMuseum(context.Background(), 99, accessOwner); err == nil || err.Error() != notFound {
+		t.Errorf("missing museum: expected museum not found, got %v", err)
+	}
+}
+
+func TestRestoreArtwork(t *testing.T) {
+	var revisions recordedRevisions
+	s, _, artworks := newTestTrashService(&revisions)
+
+	if err := s.RestoreArtwork(context.Background(), 10, 100, accessViewer); err == nil || err.Error() != "permission denied" {
+		t.Errorf("viewer: expected permission denied, got %v", err)
+	}
+	if err := s.RestoreArtwork(context.Background(), 10, 100, accessEditor); err != nil {
+		t.Fatalf("editor: %v", err)
+	}
+	if artworks.deleted[100] {
+		t.Error("artwork still in trash")
+	}
+	if len(revisions) != 1 || revisions[0] != domain.RevisionRestoreArtwork {
+		t.Errorf("revisions = %v, want [%s]", revisions, domain.RevisionRestoreArtwork)
+	}
+
+	// 元に戻した作品やゴミ箱にない作品は見つからない
+	if err := s.RestoreArtwork(context.Background(), 10, 100, accessEditor); err == nil || err.Error() != "artwork not found" {
+		t.Errorf("expected artwork not found, got %v", err)
+	}
+	if len(revisions) != 1 {
+		t.Errorf("failed restore recorded a revision: %v", revisions)
+	}
+}
+
+func TestPurgeUsesRetention(t *testing.T) {
+	s, museums, artworks := newTestTrashService(nil)
+
+	start := time.Now()
+	m, a, err := s.Purge(context.Background())
+	end := time.Now()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != 1 || a != 2 {
+		t.Errorf("purged = (%d, %d), want (1, 2)", m, a)
+	}
+
+	// 保持期間より前に削除された項目だけが対象になる
+	for name, before := range map[string]time.Time{"museums": museums.purgedBefore, "artworks": artworks.purgedBefore} {
+		if before.Before(start.Add(-trashRetention)) || before.After(end.Add(-trashRetention)) {
+			t.Errorf("%s purged before %v, want now - %v", name, before, trashRetention)
+		}
+	}
+}
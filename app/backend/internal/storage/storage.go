@@ -0,0 +1,87 @@
+// Package storage はアップロードされたファイル（ミュージアムのカバー画像など）の保存先を扱う
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore はキー（covers/1/abc.jpgのようなスラッシュ区切りのパス）でファイルを保存し、公開URLを返す
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// URL は保存したファイルをブラウザから取得するためのURL（またはパス）を返す
+	URL(key string) string
+}
+
+// validKey はキーがストアの外を指したり、空の要素を含んだりしていないかを確認する
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// LocalBlobStore はローカルディスクに保存するBlobStore
+// 保存したファイルはHandlerでbaseURL以下に公開する
+type LocalBlobStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalBlobStore はdirに保存し、baseURL（/blobsなど）以下で公開するLocalBlobStoreを作成する（dirがなければ作る）
+func NewLocalBlobStore(dir, baseURL string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &LocalBlobStore{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+// Put は一時ファイルに書き込んでからリネームし、途中までのファイルを公開しないようにする
+func (s *LocalBlobStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	p := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), filepath.Base(p)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalBlobStore) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// Handler は保存したファイルを返すハンドラー（baseURLのパスを取り除いてから渡す）
+// キーには推測できない名前を使い、内容は変わらないため長期間キャッシュさせる
+func (s *LocalBlobStore) Handler() http.Handler {
+	files := http.FileServer(http.Dir(s.dir))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// ディレクトリの一覧は返さない
+		if strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+}
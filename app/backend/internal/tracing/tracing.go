@@ -0,0 +1,114 @@
+// Package tracing はOpenTelemetryによる分散トレーシングを設定する
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName はこのアプリケーションが作るスパンの計装スコープ名
+const tracerName = "backend"
+
+// エクスポーターの種類（OTEL_TRACES_EXPORTER）
+const (
+	ExporterNone   = "none"   // スパンを記録しない
+	ExporterStdout = "stdout" // 標準出力にJSONで書き出す（ローカルでの確認用）
+	ExporterOTLP   = "otlp"   // OTLP/HTTPでコレクターへ送る（送信先はOTEL_EXPORTER_OTLP_ENDPOINT）
+)
+
+// Setup はエクスポーターとTracerProviderを作り、グローバルに登録する
+// W3C Trace Context（traceparent）とBaggageのプロパゲーターはエクスポーターに関係なく登録するため、
+// トレースを記録しない場合も上流から受け取ったトレースIDをMET APIへのリクエストに引き継ぐ
+// 戻り値の関数は終了時に呼び出し、未送信のスパンを書き出す
+func Setup(ctx context.Context, exporter string, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exp sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(exporter) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOTLP:
+		exp, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q (want none, stdout or otlp)", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Start はグローバルのTracerProviderで子スパンを開始する
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End はerrが非nilならスパンにエラーを記録してから終了する
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Transport はbaseにクライアントスパンの作成とtraceparentヘッダーの付与を加えたhttp.RoundTripperを返す
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
+
+// OpenDB はクエリごとにスパンを作るsql.DBを開く
+// スパンはコンテキストを受け取るメソッド（QueryContextなど）の呼び出しで、リクエストのスパンの子になる
+// 行の読み出しやセッションのリセットは件数が多くトレースが読みにくくなるため記録しない
+func OpenDB(driverName, dsn string) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
+}
+
+// TraceID はコンテキストのスパンのトレースID（有効なスパンがなければ空文字）を返す
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -0,0 +1,231 @@
+// Package validate はリクエスト構造体のvalidateタグに書いた宣言的なルールを検証する
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldError は1フィールド分の検証エラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Errors は検証に失敗したすべてのフィールドのエラー
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// rule は1つの検証ルール。失敗した場合はエラーメッセージを返す
+type rule struct {
+	name  string
+	check func(v reflect.Value) (string, bool)
+}
+
+// field はvalidateタグを持つフィールドとそのルール
+type field struct {
+	index    int
+	name     string
+	required bool
+	rules    []rule
+}
+
+// fieldsCache は型ごとに解析済みのルールを保持する
+var fieldsCache sync.Map // map[reflect.Type][]field
+
+// Struct はvのvalidateタグのルールをすべてのフィールドに適用し、失敗したフィールドをまとめてErrorsで返す
+// vは構造体またはそのポインタ。対応するルール:
+//
+//	required     空でないこと（文字列は空白のみも不可、数値は0以外、ポインタはnil以外）
+//	min=N        数値はN以上
+//	max=N        文字列は文字数がN以下、数値はN以下
+//	oneof=a b c  文字列がいずれかの値であること
+//	url          http・httpsの絶対URL、または/で始まるパス（/assets/room.jpgなど）であること
+//
+// required以外のルールは、値が空（ゼロ値・nil）のときは検査しない
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validate: %T is not a struct", v))
+	}
+
+	var errs Errors
+	for _, f := range fieldsOf(rv.Type()) {
+		fv := rv.Field(f.index)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				if f.required {
+					errs = append(errs, FieldError{Field: f.name, Message: "is required"})
+				}
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if isEmpty(fv) {
+			if f.required {
+				errs = append(errs, FieldError{Field: f.name, Message: "is required"})
+			}
+			continue
+		}
+
+		for _, r := range f.rules {
+			if msg, ok := r.check(fv); !ok {
+				errs = append(errs, FieldError{Field: f.name, Message: msg})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// fieldsOf は型のvalidateタグを解析する。結果は型ごとにキャッシュする
+func fieldsOf(t reflect.Type) []field {
+	if cached, ok := fieldsCache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("validate")
+		if !ok || tag == "" || tag == "-" {
+			continue
+		}
+
+		f := field{index: i, name: jsonName(sf)}
+		for _, spec := range strings.Split(tag, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(spec), "=")
+			if name == "required" {
+				f.required = true
+				continue
+			}
+			f.rules = append(f.rules, newRule(t, sf, name, arg))
+		}
+		fields = append(fields, f)
+	}
+
+	fieldsCache.Store(t, fields)
+	return fields
+}
+
+// jsonName はエラーに使うフィールド名としてJSONのキー名を返す
+func jsonName(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// newRule はタグの1要素からルールを作る。タグの誤りはプログラムの誤りなのでpanicする
+func newRule(t reflect.Type, sf reflect.StructField, name, arg string) rule {
+	invalid := func(reason string) {
+		panic(fmt.Sprintf("validate: %s.%s: %s", t.Name(), sf.Name, reason))
+	}
+
+	kind := sf.Type.Kind()
+	if kind == reflect.Pointer {
+		kind = sf.Type.Elem().Kind()
+	}
+	switch {
+	case kind == reflect.String:
+	case isIntKind(kind) && (name == "min" || name == "max"):
+	default:
+		invalid(fmt.Sprintf("%s cannot be applied to %s fields", name, kind))
+	}
+
+	switch name {
+	case "min", "max":
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			invalid(fmt.Sprintf("invalid %s argument %q", name, arg))
+		}
+		if name == "min" {
+			return rule{name: name, check: func(v reflect.Value) (string, bool) {
+				if isIntKind(v.Kind()) {
+					return fmt.Sprintf("must be at least %d", n), v.Int() >= n
+				}
+				return fmt.Sprintf("must be at least %d characters", n), int64(utf8.RuneCountInString(v.String())) >= n
+			}}
+		}
+		return rule{name: name, check: func(v reflect.Value) (string, bool) {
+			if isIntKind(v.Kind()) {
+				return fmt.Sprintf("must be at most %d", n), v.Int() <= n
+			}
+			return fmt.Sprintf("must be at most %d characters", n), int64(utf8.RuneCountInString(v.String())) <= n
+		}}
+	case "oneof":
+		values := strings.Fields(arg)
+		if len(values) == 0 {
+			invalid("oneof requires at least one value")
+		}
+		msg := "must be one of " + strings.Join(values, ", ")
+		return rule{name: name, check: func(v reflect.Value) (string, bool) {
+			for _, allowed := range values {
+				if v.String() == allowed {
+					return msg, true
+				}
+			}
+			return msg, false
+		}}
+	case "url":
+		return rule{name: name, check: func(v reflect.Value) (string, bool) {
+			return "must be an http(s) URL or a path starting with /", isURL(v.String())
+		}}
+	}
+
+	invalid(fmt.Sprintf("unknown rule %q", name))
+	return rule{}
+}
+
+// isURL はhttp・httpsの絶対URL、または同一オリジンのパスかどうかを返す
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		return u.Host != ""
+	}
+	return u.Scheme == "" && u.Host == "" && strings.HasPrefix(s, "/") && !strings.HasPrefix(s, "//")
+}
+
+// isIntKind は整数型かどうかを返す
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// isEmpty は値がrequiredを満たさないかどうかを返す
+func isEmpty(v reflect.Value) bool {
+	if v.Kind() == reflect.String {
+		return strings.TrimSpace(v.String()) == ""
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,92 @@
+package validate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testRequest struct {
+	UserID   int     `json:"userId" validate:"required,min=1"`
+	Name     string  `json:"name" validate:"required,max=5"`
+	Kind     string  `json:"kind" validate:"oneof=a b"`
+	ImageURL string  `json:"imageUrl" validate:"max=30,url"`
+	Note     *string `json:"note,omitempty" validate:"max=3"`
+	Free     string  `json:"free"`
+}
+
+func fieldMessages(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validate.Errors, got %v", err)
+	}
+	got := map[string]string{}
+	for _, fe := range errs {
+		got[fe.Field] = fe.Message
+	}
+	return got
+}
+
+func TestStruct_Valid(t *testing.T) {
+	note := "abc"
+	req := testRequest{UserID: 1, Name: "名前です", Kind: "a", ImageURL: "https://example.com/a.jpg", Note: &note}
+	if err := Struct(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Struct(testRequest{UserID: 1, Name: "ok", ImageURL: "/assets/room.jpg"}); err != nil {
+		t.Fatalf("path should pass url rule: %v", err)
+	}
+	// 任意のフィールドは空なら検査しない
+	if err := Struct(&testRequest{UserID: 1, Name: "ok"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStruct_ReportsEveryFailingField(t *testing.T) {
+	note := "too long"
+	req := testRequest{UserID: -1, Name: "   ", Kind: "c", ImageURL: "ftp://example.com/a.jpg", Note: &note}
+
+	got := fieldMessages(t, Struct(req))
+	want := map[string]string{
+		"userId":   "must be at least 1",
+		"name":     "is required",
+		"kind":     "must be one of a, b",
+		"imageUrl": "must be an http(s) URL or a path starting with /",
+		"note":     "must be at most 3 characters",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), got)
+	}
+	for field, msg := range want {
+		if got[field] != msg {
+			t.Errorf("%s: expected %q, got %q", field, msg, got[field])
+		}
+	}
+}
+
+func TestStruct_MaxLengthCountsCharacters(t *testing.T) {
+	if err := Struct(testRequest{UserID: 1, Name: "あいうえお"}); err != nil {
+		t.Fatalf("5 characters should pass max=5: %v", err)
+	}
+
+	got := fieldMessages(t, Struct(testRequest{UserID: 1, Name: "あいうえおか", ImageURL: "https://example.com/" + strings.Repeat("a", 20)}))
+	if got["name"] != "must be at most 5 characters" {
+		t.Errorf("name: unexpected message %q", got["name"])
+	}
+	if _, ok := got["imageUrl"]; !ok {
+		t.Errorf("imageUrl: expected max length error")
+	}
+}
+
+func TestStruct_InvalidTagPanics(t *testing.T) {
+	type badRequest struct {
+		Count int `validate:"url"`
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for url rule on int field")
+		}
+	}()
+	_ = Struct(badRequest{Count: 1})
+}
@@ -8,6 +8,14 @@ import {
   searchArtworks,
   fetchMetObject,
 } from '../lib/api'
+import { getCurrentUserId } from '../lib/session'
+
+// タイトル更新はログイン中のユーザー（VITE_DEV_USER_ID）として行う。未設定の場合は失敗する
+const requireCallerId = () => {
+  const id = getCurrentUserId()
+  if (id === undefined) throw new Error('VITE_DEV_USER_ID is not set')
+  return id
+}
 
 export default function ApiTestPage() {
   const [results, setResults] = useState<Record<string, any>>({})
@@ -66,7 +74,7 @@ export default function ApiTestPage() {
     {
       name: 'updateMuseumTitle',
       label: 'ミュージアムタイトル更新',
-      fn: () => updateMuseumTitle(12, 'Updated Title from Frontend') // 存在するIDを使用
+      fn: () => updateMuseumTitle(12, 'Updated Title from Frontend', requireCallerId(), 1) // 存在するID・バージョンを使用
     },
     {
       name: 'searchArtworks',
@@ -86,6 +94,7 @@ export default function ApiTestPage() {
       console.log('🔍 [TEST] Getting available museum IDs...')
       const museums = await fetchPublicMuseums(1, 5)
       const availableId = museums.length > 0 ? museums[0].id : 12
+      const availableVersion = museums.length > 0 ? museums[0].version : 1
       console.log('📋 [TEST] Using museum ID:', availableId)
 
       // 動的にテストを更新
@@ -94,7 +103,7 @@ export default function ApiTestPage() {
           return { ...test, fn: () => fetchMuseumById(availableId) }
         }
         if (test.name === 'updateMuseumTitle') {
-          return { ...test, fn: () => updateMuseumTitle(availableId, `Updated Title ${Date.now()}`) }
+          return { ...test, fn: () => updateMuseumTitle(availableId, `Updated Title ${Date.now()}`, requireCallerId(), availableVersion) }
         }
         return test
       })
@@ -1,10 +1,13 @@
 import React, { useState, useEffect } from 'react'
-import { useParams } from 'react-router-dom'
+import { useParams, useSearchParams } from 'react-router-dom'
 import MuseumPicture from './MuseumPicture'
 import { fetchMuseumItemById } from '../lib/api'
 
 const MuseumScreen = () => {
   const { museumId } = useParams<{ museumId: string }>()
+  // 共有リンク（?token=）から開いた場合は、非公開ミュージアムもトークンで閲覧する
+  const [searchParams] = useSearchParams()
+  const shareToken = searchParams.get('token') ?? undefined
   const [title, setTitle] = useState('')
   const [imageUrl, setImageUrl] = useState(
     'https://placehold.jp/eeeeee/cccccc/330x200.png?text=No%20Image',
@@ -23,7 +26,7 @@ const MuseumScreen = () => {
 
       try {
         setLoading(true)
-        const museum = await fetchMuseumItemById(parseInt(museumId))
+        const museum = await fetchMuseumItemById(parseInt(museumId), { token: shareToken })
         setTitle(museum.name)
         if (museum.imageUrl) {
           setImageUrl(museum.imageUrl)
@@ -38,7 +41,7 @@ const MuseumScreen = () => {
     }
 
     loadMuseum()
-  }, [museumId])
+  }, [museumId, shareToken])
 
   if (loading) {
     return (
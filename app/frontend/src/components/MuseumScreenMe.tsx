@@ -1,15 +1,22 @@
 import React, { useState, useEffect } from 'react'
-import { useParams } from 'react-router-dom'
+import { useParams, useSearchParams } from 'react-router-dom'
 import { IconContext } from 'react-icons'
 import { MdOutlineEdit, MdOutlineCheck } from 'react-icons/md'
 import MuseumPicture from './MuseumPicture'
-import { fetchMuseumItemById, updateMuseumTitle } from '../lib/api'
+import { ApiError, fetchMuseumItemById, updateMuseumTitle } from '../lib/api'
+import { getCurrentUserId } from '../lib/session'
 
 const MuseumScreenMe = () => {
   const { museumId } = useParams<{ museumId: string }>()
+  // 共有リンク（?token=）から開いた場合は、非公開ミュージアムもトークンで閲覧する
+  const [searchParams] = useSearchParams()
+  const shareToken = searchParams.get('token') ?? undefined
+  // 呼び出し元はログイン中のユーザー。取得したミュージアムの所有者IDを使うと誰でも所有者になりすませてしまう
+  const callerId = getCurrentUserId()
   const [isEditing, setIsEditing] = useState(false)
   const [title, setTitle] = useState('')
   const [originalTitle, setOriginalTitle] = useState('')
+  const [version, setVersion] = useState<number | null>(null)
   const [imageUrl, setImageUrl] = useState(
     'https://placehold.jp/eeeeee/cccccc/330x200.png?text=No%20Image',
   )
@@ -28,9 +35,10 @@ const MuseumScreenMe = () => {
 
       try {
         setLoading(true)
-        const museum = await fetchMuseumItemById(parseInt(museumId))
+        const museum = await fetchMuseumItemById(parseInt(museumId), { token: shareToken })
         setTitle(museum.name)
         setOriginalTitle(museum.name)
+        setVersion(museum.version)
         if (museum.imageUrl) {
           setImageUrl(museum.imageUrl)
         }
@@ -44,11 +52,11 @@ const MuseumScreenMe = () => {
     }
 
     loadMuseum()
-  }, [museumId])
+  }, [museumId, shareToken])
 
   // タイトル保存処理
   const handleSaveTitle = async () => {
-    if (!museumId || title.trim() === '') {
+    if (!museumId || callerId === undefined || version === null || title.trim() === '') {
       setError('タイトルを入力してください')
       return
     }
@@ -60,13 +68,19 @@ const MuseumScreenMe = () => {
 
     try {
       setSaving(true)
-      await updateMuseumTitle(parseInt(museumId), title.trim())
+      const res = await updateMuseumTitle(parseInt(museumId), title.trim(), callerId, version)
+      setVersion(res.version)
       setOriginalTitle(title.trim())
       setIsEditing(false)
       setError(null)
     } catch (err) {
       console.error('Failed to update title:', err)
-      setError('タイトルの更新に失敗しました')
+      if (err instanceof ApiError && err.status === 412) {
+        // 取得後に他で更新されている。古い内容で上書きしないよう再読み込みしてもらう
+        setError('他のユーザーが更新しました。ページを再読み込みしてからやり直してください')
+      } else {
+        setError('タイトルの更新に失敗しました')
+      }
       setTitle(originalTitle) // 元のタイトルに戻す
     } finally {
       setSaving(false)
@@ -166,7 +180,8 @@ const MuseumScreenMe = () => {
               <button
                 className="ml-2 p-1 rounded font-bold text-gray-800 disabled:opacity-50"
                 onClick={handleEditClick}
-                disabled={saving}
+                disabled={saving || callerId === undefined}
+                title={callerId === undefined ? 'タイトルを編集するにはログインが必要です' : undefined}
               >
                 <IconContext.Provider value={{ size: '1.5em' }}>
                   {saving ? (
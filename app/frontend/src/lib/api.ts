@@ -1,5 +1,6 @@
 import { z } from 'zod'
 import { ItemSchema } from './types'
+import { getCurrentUserId } from './session'
 
 const ItemsSchema = z.array(ItemSchema)
 
@@ -41,9 +42,45 @@ export async function postItem(body: { name: string }) {
 
 // ============ Museum API Functions (developブランチの既存実装) ============
 
+/**
+ * ステータスコード付きのAPIエラー（412 などを呼び出し側で見分けるため）
+ */
+export class ApiError extends Error {
+  constructor(
+    message: string,
+    readonly status: number,
+  ) {
+    super(message)
+    this.name = 'ApiError'
+  }
+}
+
+/**
+ * ミュージアム取得時の呼び出し元
+ * userId を省略するとログイン中のユーザー（getCurrentUserId）を使う。token は共有リンクの ?token= の値
+ */
+export type MuseumAccessOptions = {
+  userId?: number
+  token?: string
+}
+
+// 非公開ミュージアムを所有者・メンバー・共有リンク経由で取得できるよう、X-User-ID と ?token= を付ける
+function museumRequest(path: string, opts: MuseumAccessOptions = {}, params?: URLSearchParams) {
+  const query = new URLSearchParams(params)
+  if (opts.token) query.set('token', opts.token)
+  const qs = query.toString()
+
+  const headers: Record<string, string> = {}
+  const userId = opts.userId ?? getCurrentUserId()
+  if (userId !== undefined) headers['X-User-ID'] = userId.toString()
+
+  return fetch(`${base}${path}${qs ? `?${qs}` : ''}`, { headers })
+}
+
 // [GET] /api/v1/museums?exclude_user_id=1
-export async function fetchMuseumItems(userId: number) {
-  const res = await fetch(`${base}/api/v1/museums?excludeUserId=${userId}&limit=10`)
+export async function fetchMuseumItems(userId: number, opts?: MuseumAccessOptions) {
+  const params = new URLSearchParams({ excludeUserId: userId.toString(), limit: '10' })
+  const res = await museumRequest('/api/v1/museums', opts, params)
   if (!res.ok) throw new Error(`Failed to fetch museums: ${res.status}`)
   const json = await res.json()
   return json
@@ -59,10 +96,11 @@ export const MuseumSchema = z.object({
   visibility: z.string(),
   imageUrl: z.string(),
   createdAt: z.string(),
+  version: z.number(),
 })
 
-export async function fetchMuseumItemById(museumId: number) {
-  const res = await fetch(`${base}/api/v1/museums/${museumId}`)
+export async function fetchMuseumItemById(museumId: number, opts?: MuseumAccessOptions) {
+  const res = await museumRequest(`/api/v1/museums/${museumId}`, opts)
   if (!res.ok) throw new Error(`Failed to fetch museum item: ${res.status}`)
   const json = await res.json()
   const parsed = MuseumSchema.safeParse(json)
@@ -80,9 +118,10 @@ const ExtendedMuseumSchema = z.object({
   userId: z.number(),
   name: z.string(),
   description: z.string().nullable(),
-  visibility: z.enum(['public', 'private']),
+  visibility: z.enum(['public', 'private', 'unlisted']),
   imageUrl: z.string().nullable(),
   createdAt: z.string(),
+  version: z.number(),
 })
 
 const MuseumsSchema = z.array(ExtendedMuseumSchema)
@@ -91,7 +130,7 @@ const CreateMuseumSchema = z.object({
   userId: z.number(),
   name: z.string(),
   description: z.string().optional(),
-  visibility: z.enum(['public', 'private']).optional(),
+  visibility: z.enum(['public', 'private', 'unlisted']).optional(),
   imageUrl: z.string().optional(),
 })
 
@@ -129,13 +168,17 @@ export type MetObject = z.infer<typeof MetObjectSchema>
 /**
  * 公開ミュージアム取得（指定ユーザー以外） - 拡張版
  */
-export async function fetchPublicMuseums(excludeUserId: number, limit: number = 10): Promise<Museum[]> {
+export async function fetchPublicMuseums(
+  excludeUserId: number,
+  limit: number = 10,
+  opts?: MuseumAccessOptions,
+): Promise<Museum[]> {
   const params = new URLSearchParams({
     excludeUserId: excludeUserId.toString(),
     limit: limit.toString(),
   })
 
-  const res = await fetch(`${base}/api/v1/museums?${params}`)
+  const res = await museumRequest('/api/v1/museums', opts, params)
   if (!res.ok) {
     const err = await res.json().catch(() => ({}))
     throw new Error(err?.error ?? `Failed to fetch museums: ${res.status}`)
@@ -152,8 +195,8 @@ export async function fetchPublicMuseums(excludeUserId: number, limit: number =
 /**
  * ミュージアム詳細取得 - 拡張版
  */
-export async function fetchMuseumById(id: number): Promise<Museum> {
-  const res = await fetch(`${base}/api/v1/museums/${id}`)
+export async function fetchMuseumById(id: number, opts?: MuseumAccessOptions): Promise<Museum> {
+  const res = await museumRequest(`/api/v1/museums/${id}`, opts)
   if (!res.ok) {
     const err = await res.json().catch(() => ({}))
     throw new Error(err?.error ?? `Failed to fetch museum: ${res.status}`)
@@ -191,18 +234,29 @@ export async function createMuseum(museum: CreateMuseumRequest): Promise<Museum>
 }
 
 /**
- * ミュージアムタイトル更新
+ * ミュージアムタイトル更新（owner・editor のみ）
+ * userId にはログイン中のユーザー（getCurrentUserId）を渡す。取得したミュージアムの userId を渡してはいけない
+ * version には取得時のバージョンを渡す。他で更新されていると status 412 の ApiError で失敗する
  */
-export async function updateMuseumTitle(id: number, title: string): Promise<{ message: string }> {
+export async function updateMuseumTitle(
+  id: number,
+  title: string,
+  userId: number,
+  version: number,
+): Promise<{ message: string; version: number }> {
   const res = await fetch(`${base}/api/v1/museums/${id}/title`, {
     method: 'PATCH',
-    headers: { 'Content-Type': 'application/json' },
+    headers: {
+      'Content-Type': 'application/json',
+      'X-User-ID': userId.toString(),
+      'If-Match': `"${version}"`,
+    },
     body: JSON.stringify({ title }),
   })
 
   if (!res.ok) {
     const err = await res.json().catch(() => ({}))
-    throw new Error(err?.error ?? `Failed to update museum title: ${res.status}`)
+    throw new ApiError(err?.error ?? `Failed to update museum title: ${res.status}`, res.status)
   }
 
   return await res.json()
@@ -0,0 +1,13 @@
+/**
+ * 呼び出し元ユーザー（X-User-ID に送るID）
+ *
+ * ログイン機能ができるまでは、開発用に VITE_DEV_USER_ID で指定したユーザーとして振る舞う。
+ * 未設定の場合はログインしていない閲覧者として扱い、X-User-ID を送らない。
+ * 取得したミュージアムの userId などをここの代わりに使ってはいけない（誰でも所有者になりすませてしまう）
+ */
+export function getCurrentUserId(): number | undefined {
+  const raw = import.meta.env.VITE_DEV_USER_ID as string | undefined
+  if (!raw) return undefined
+  const id = Number(raw)
+  return Number.isInteger(id) && id > 0 ? id : undefined
+}